@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	pb "distributed-task-scheduler/proto"
+)
+
+// runCancel implements `client cancel <job-id>...`.
+func runCancel(args []string) {
+	fs := flag.NewFlagSet("cancel", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		log.Fatalf("Usage: client cancel [-server=addr] <job-id>...")
+	}
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+
+	failed := false
+	for _, id := range fs.Args() {
+		resp, err := client.CancelJob(context.Background(), &pb.JobId{Id: id})
+		if err != nil {
+			log.Printf("Failed to cancel job %s: %v", id, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%s: %s\n", id, resp.Message)
+		if !resp.Success {
+			failed = true
+		}
+	}
+	if failed {
+		log.Fatalf("Some jobs could not be cancelled")
+	}
+}
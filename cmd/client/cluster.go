@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "distributed-task-scheduler/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// runCluster implements `client cluster`, asking every server for its view of the leader
+// election and printing one line per server, followed by the leader's last maintenance tick.
+func runCluster(args []string) {
+	fs := flag.NewFlagSet("cluster", flag.ExitOnError)
+	serversFlag := fs.String("servers", "", "Comma-separated list of server addresses (defaults to SERVERS env or localhost:50051)")
+	fs.Parse(args)
+
+	servers := splitAndTrim(*serversFlag)
+	if len(servers) == 0 {
+		servers = splitAndTrim(os.Getenv("SERVERS"))
+	}
+	if len(servers) == 0 {
+		servers = []string{defaultServerAddr}
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tNODE\tROLE\tELECTION\tLEADER\tEPOCH\tLEADER SINCE\tLEASE TTL")
+	var scans []string
+	for _, addr := range servers {
+		st, err := clusterStatus(addr)
+		if err != nil {
+			fmt.Fprintf(tw, "%s\t-\tUNREACHABLE\t-\t-\t-\t-\t-\n", addr)
+			log.Printf("Failed to get cluster status from %s: %v", addr, err)
+			continue
+		}
+		election := st.Election
+		if election == "" {
+			election = "-"
+		}
+		leader := st.LeaderId
+		if leader == "" {
+			leader = "-"
+		}
+		ttl := "-"
+		if st.LeaseTtlSeconds > 0 {
+			ttl = (time.Duration(st.LeaseTtlSeconds) * time.Second).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n", addr, st.NodeId, st.Role, election, leader, st.LeaderEpoch, formatUnix(st.LeaderChangedAt), ttl)
+		if sc := st.LastScan; sc != nil && st.LeaderDutiesRunning {
+			scans = append(scans, fmt.Sprintf("%s (%s) last tick %s: %d stale failed, %d retries promoted, %d due enqueued, %d orphans reclaimed, %d workflow jobs skipped, %d released",
+				st.NodeId, addr, formatUnix(sc.At), sc.StaleFailed, sc.DelayedPromoted, sc.DueEnqueued, sc.OrphansReclaimed, sc.WorkflowSkipped, sc.WorkflowReleased))
+		}
+	}
+	tw.Flush()
+	for _, line := range scans {
+		fmt.Printf("\n%s\n", line)
+	}
+}
+
+func clusterStatus(addr string) (*pb.ClusterStatus, error) {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return pb.NewClusterServiceClient(conn).GetClusterStatus(ctx, &pb.GetClusterStatusRequest{})
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "distributed-task-scheduler/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const dlqUsage = "Usage: client dlq list|show|replay|purge [flags]"
+
+// runDLQ implements `client dlq <list|show|replay|purge>`.
+func runDLQ(args []string) {
+	if len(args) == 0 {
+		log.Fatalf(dlqUsage)
+	}
+	switch args[0] {
+	case "list":
+		runDLQList(args[1:])
+	case "show":
+		runDLQShow(args[1:])
+	case "replay":
+		runDLQReplay(args[1:])
+	case "purge":
+		runDLQPurge(args[1:])
+	default:
+		log.Fatalf(dlqUsage)
+	}
+}
+
+func runDLQList(args []string) {
+	fs := flag.NewFlagSet("dlq list", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	queueName := fs.String("queue", "", "Only this queue (default: all queues)")
+	limit := fs.Int("limit", 50, "Maximum number of entries")
+	fs.Parse(args)
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+	resp, err := client.ListDeadLetters(context.Background(), &pb.ListDeadLettersRequest{Queue: *queueName, Limit: int32(*limit)})
+	if err != nil {
+		log.Fatalf("Failed to list dead letters: %v", err)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tQUEUE\tSTATUS\tATTEMPTS\tFAILED\tCOMMAND")
+	for _, d := range resp.DeadLetters {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n", d.Id, d.Queue, d.Status, d.Attempts, formatUnix(d.FailedAt), d.Command)
+	}
+	tw.Flush()
+	if int(resp.Total) > len(resp.DeadLetters) {
+		fmt.Printf("\nShowing %d of %d dead letters\n", len(resp.DeadLetters), resp.Total)
+	}
+}
+
+func runDLQShow(args []string) {
+	fs := flag.NewFlagSet("dlq show", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatalf("Usage: client dlq show [-server=addr] <job-id>")
+	}
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+	st, err := client.GetJobStatus(context.Background(), &pb.JobId{Id: fs.Arg(0)})
+	if err != nil {
+		log.Fatalf("Failed to get job %s: %v", fs.Arg(0), err)
+	}
+	fmt.Printf("ID:       %s\n", st.Id)
+	fmt.Printf("Queue:    %s\n", st.Queue)
+	fmt.Printf("Status:   %s\n", st.Status)
+	fmt.Printf("Updated:  %s\n", formatUnix(st.UpdatedAt))
+	fmt.Printf("Output:\n%s\n", st.Output)
+}
+
+func runDLQReplay(args []string) {
+	fs := flag.NewFlagSet("dlq replay", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		log.Fatalf("Usage: client dlq replay [-server=addr] <job-id>...")
+	}
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+	failed := false
+	for _, id := range fs.Args() {
+		resp, err := client.ReplayDeadLetter(context.Background(), &pb.JobId{Id: id})
+		if err != nil {
+			log.Printf("Failed to replay job %s: %v", id, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%s: %s\n", id, resp.Message)
+		if !resp.Success {
+			failed = true
+		}
+	}
+	if failed {
+		log.Fatalf("Some jobs could not be replayed")
+	}
+}
+
+func runDLQPurge(args []string) {
+	fs := flag.NewFlagSet("dlq purge", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	queueName := fs.String("queue", "", "Only this queue (default: all queues)")
+	olderThan := fs.Duration("older-than", 0, "Only jobs that failed longer ago than this, e.g. 24h")
+	command := fs.String("command", "", "Only jobs whose command contains this text")
+	all := fs.Bool("all", false, "Purge every matching dead letter even without other filters")
+	fs.Parse(args)
+
+	req := &pb.PurgeDeadLettersRequest{
+		Queue:           *queueName,
+		Ids:             fs.Args(),
+		CommandContains: *command,
+		All:             *all,
+	}
+	if *olderThan > 0 {
+		req.FailedBefore = time.Now().Add(-*olderThan).Unix()
+	}
+	if !req.All && len(req.Ids) == 0 && req.FailedBefore == 0 && req.CommandContains == "" {
+		log.Fatalf("Usage: client dlq purge [-queue=name] [-older-than=d] [-command=text] [-all] [job-id...]")
+	}
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+	resp, err := client.PurgeDeadLetters(context.Background(), req)
+	if err != nil {
+		log.Fatalf("Failed to purge dead letters: %v", err)
+	}
+	fmt.Printf("Purged %d dead letters\n", resp.Purged)
+}
+
+// dialJobService connects to the server chosen by -server, SERVERS or the default.
+func dialJobService(serverFlag string) (pb.JobServiceClient, *grpc.ClientConn) {
+	addr := resolveServer(serverFlag)
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to server %s: %v", addr, err)
+	}
+	return pb.NewJobServiceClient(conn), conn
+}
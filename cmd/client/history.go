@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "distributed-task-scheduler/proto"
+)
+
+// runHistory implements `client history <job-id>`, printing every attempt of a job.
+func runHistory(args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	showOutput := fs.Bool("output", false, "Also print the output of each attempt")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatalf("Usage: client history [-server=addr] [-output] <job-id>")
+	}
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+	history, err := client.GetJobHistory(context.Background(), &pb.JobId{Id: fs.Arg(0)})
+	if err != nil {
+		log.Fatalf("Failed to get job history: %v", err)
+	}
+	if len(history.Attempts) == 0 {
+		fmt.Printf("Job %s has not run yet\n", history.JobId)
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ATTEMPT\tSTATUS\tWORKER\tHOST\tSTARTED\tFINISHED\tDURATION\tEXIT")
+	for _, a := range history.Attempts {
+		exit := "-"
+		if r := a.Result; r != nil {
+			exit = fmt.Sprint(r.ExitCode)
+			if r.Signal != "" {
+				exit = r.Signal
+			}
+		}
+		duration := (time.Duration(a.DurationMs) * time.Millisecond).String()
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", a.Attempt, a.Status, a.WorkerId, a.Host, formatUnix(a.StartedAt), formatUnix(a.FinishedAt), duration, exit)
+	}
+	tw.Flush()
+
+	if !*showOutput {
+		return
+	}
+	for _, a := range history.Attempts {
+		if a.Output == "" {
+			continue
+		}
+		fmt.Printf("\n--- attempt %d ---\n%s\n", a.Attempt, strings.TrimRight(a.Output, "\n"))
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "distributed-task-scheduler/proto"
+)
+
+// runList implements `client list`, printing jobs that match the given filters.
+func runList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	statusFlag := fs.String("status", "", "Comma-separated statuses, e.g. RUNNING,FAILED")
+	createdSince := fs.Duration("created-since", 0, "Only jobs created within this duration, e.g. 1h")
+	updatedSince := fs.Duration("updated-since", 0, "Only jobs updated within this duration, e.g. 1h")
+	command := fs.String("command", "", "Only jobs whose command contains this text")
+	kind := fs.String("kind", "", "Only cron or oneshot jobs")
+	queueName := fs.String("queue", "", "Only jobs routed to this queue")
+	orderBy := fs.String("order-by", "created_at", "Sort by created_at or updated_at")
+	asc := fs.Bool("asc", false, "Oldest first")
+	limit := fs.Int("limit", 50, "Page size")
+	pageToken := fs.String("page-token", "", "Continue from a previous page")
+	all := fs.Bool("all", false, "Fetch every page instead of only the first")
+	fs.Parse(args)
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+
+	req := &pb.ListJobsRequest{
+		Statuses:        splitAndTrim(*statusFlag),
+		CommandContains: *command,
+		Kind:            *kind,
+		Queue:           *queueName,
+		OrderBy:         *orderBy,
+		Ascending:       *asc,
+		PageSize:        int32(*limit),
+		PageToken:       *pageToken,
+	}
+	now := time.Now()
+	if *createdSince > 0 {
+		req.CreatedAfter = now.Add(-*createdSince).Unix()
+	}
+	if *updatedSince > 0 {
+		req.UpdatedAfter = now.Add(-*updatedSince).Unix()
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tQUEUE\tPRIO\tCREATED\tUPDATED\tNEXT RUN\tCRON")
+	for {
+		resp, err := client.ListJobs(context.Background(), req)
+		if err != nil {
+			log.Fatalf("Failed to list jobs: %v", err)
+		}
+		for _, j := range resp.Jobs {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n", j.Id, j.Status, j.Queue, j.Priority, formatUnix(j.CreatedAt), formatUnix(j.UpdatedAt), formatUnix(j.NextRunAt), j.CronExpr)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		if !*all {
+			tw.Flush()
+			fmt.Printf("\nMore results: -page-token=%s\n", resp.NextPageToken)
+			return
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	tw.Flush()
+}
+
+// resolveServer picks a single server address: the flag, then SERVERS env, then the default.
+func resolveServer(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if env := splitAndTrim(os.Getenv("SERVERS")); len(env) > 0 {
+		return env[0]
+	}
+	return defaultServerAddr
+}
+
+func formatUnix(ts int64) string {
+	if ts <= 0 {
+		return "-"
+	}
+	return time.Unix(ts, 0).Format(time.DateTime)
+}
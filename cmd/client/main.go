@@ -14,19 +14,44 @@ import (
 
 	pb "distributed-task-scheduler/proto"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	defaultServerAddr = "localhost:50051"
+
+	// Submissions carry an idempotency key, so calls that failed in transit are retried
+	submitAttempts = 4
+	submitTimeout  = 10 * time.Second
 )
 
 // JobConfig represents a single job configuration from JSON
 type JobConfig struct {
-	Name        string `json:"name"`
-	Command     string `json:"command"`
-	Description string `json:"description,omitempty"`
+	Name         string       `json:"name"`
+	Command      string       `json:"command"`
+	Description  string       `json:"description,omitempty"`
+	ScheduleTime int64        `json:"schedule_time,omitempty"`   // Unix seconds; run once at this time
+	CronExpr     string       `json:"cron_expr,omitempty"`       // Recurring schedule, e.g. "*/5 * * * *"
+	Timezone     string       `json:"timezone,omitempty"`        // IANA zone for cron_expr
+	Timeout      int64        `json:"timeout_seconds,omitempty"` // Kill the command after this many seconds
+	Priority     int32        `json:"priority,omitempty"`        // Higher runs first
+	Queue        string       `json:"queue,omitempty"`           // Named queue, default "default"
+	Retry        *RetryConfig `json:"retry_policy,omitempty"`    // Retry and backoff settings
+	// IdempotencyKey deduplicates resubmissions; a random key is used when empty
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// RetryConfig is the retry_policy of a job; zero fields use the server defaults
+type RetryConfig struct {
+	MaxAttempts           int32   `json:"max_attempts,omitempty"`
+	InitialBackoffSeconds int64   `json:"initial_backoff_seconds,omitempty"`
+	BackoffMultiplier     float64 `json:"backoff_multiplier,omitempty"`
+	MaxBackoffSeconds     int64   `json:"max_backoff_seconds,omitempty"`
+	RetryableExitCodes    []int32 `json:"retryable_exit_codes,omitempty"`
 }
 
 // JobsFile represents the structure of the JSON configuration file
@@ -40,10 +65,44 @@ type JobResult struct {
 	JobID  string
 	Status string
 	Output string
+	Result *pb.ExecutionResult
 	Error  error
 }
 
 func main() {
+	// Subcommands; without one the client submits jobs from a JSON file
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "list":
+			runList(os.Args[2:])
+			return
+		case "cancel":
+			runCancel(os.Args[2:])
+			return
+		case "dlq":
+			runDLQ(os.Args[2:])
+			return
+		case "workflow":
+			runWorkflow(os.Args[2:])
+			return
+		case "watch":
+			runWatch(os.Args[2:])
+			return
+		case "logs":
+			runLogs(os.Args[2:])
+			return
+		case "history":
+			runHistory(os.Args[2:])
+			return
+		case "cluster":
+			runCluster(os.Args[2:])
+			return
+		case "workers":
+			runWorkers(os.Args[2:])
+			return
+		}
+	}
+
 	// Command line flags
 	jsonFile := flag.String("file", "jobs.json", "JSON file containing jobs to execute")
 	concurrent := flag.Bool("concurrent", false, "Run jobs concurrently instead of sequentially")
@@ -199,19 +258,42 @@ func processJob(ctx context.Context, submitClient pb.JobServiceClient, statusCli
 	result := JobResult{Config: jobConfig}
 
 	// Submit job
-	job := &pb.Job{
-		Command:   jobConfig.Command,
-		CreatedAt: time.Now().Unix(),
+	job := buildJob(jobConfig)
+	if job.IdempotencyKey == "" {
+		job.IdempotencyKey = uuid.New().String()
 	}
 
-	resp, err := submitClient.SubmitJob(ctx, job)
+	resp, err := submitJob(ctx, submitClient, job)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to submit job: %v", err)
 		return result
 	}
 
 	result.JobID = resp.JobId
-	log.Printf("[%s] Job submitted successfully. Job ID: %s", jobConfig.Name, resp.JobId)
+	if resp.Duplicate {
+		log.Printf("[%s] Job was already submitted. Job ID: %s, status: %s", jobConfig.Name, resp.JobId, resp.Status)
+	} else {
+		log.Printf("[%s] Job submitted successfully. Job ID: %s", jobConfig.Name, resp.JobId)
+	}
+
+	// Recurring jobs never reach a final status; report the schedule and move on
+	if jobConfig.CronExpr != "" {
+		result.Status = "SCHEDULED"
+		if status, err := statusClients[idx%len(statusClients)].GetJobStatus(ctx, &pb.JobId{Id: resp.JobId}); err == nil && status.NextRunAt > 0 {
+			result.Output = fmt.Sprintf("Next run at %s", time.Unix(status.NextRunAt, 0).Format(time.RFC3339))
+		}
+		return result
+	}
+
+	// Follow the job as the server pushes status changes
+	status, err := watchJob(ctx, statusClients[idx%len(statusClients)], resp.JobId)
+	if err == nil {
+		result.Status = status.Status
+		result.Output = status.Output
+		result.Result = status.Result
+		return result
+	}
+	log.Printf("[%s] Watching job failed, polling instead: %v", jobConfig.Name, err)
 
 	// Poll for job status from status servers (round-robin)
 	for attempt := 0; ; attempt++ {
@@ -225,8 +307,9 @@ func processJob(ctx context.Context, submitClient pb.JobServiceClient, statusCli
 
 		result.Status = status.Status
 		result.Output = status.Output
+		result.Result = status.Result
 
-		if status.Status == "SUCCEEDED" || status.Status == "FAILED" {
+		if isFinalStatus(status.Status) {
 			break
 		}
 
@@ -236,6 +319,38 @@ func processJob(ctx context.Context, submitClient pb.JobServiceClient, statusCli
 	return result
 }
 
+// submitJob calls SubmitJob, retrying when the call may not have reached the server or its
+// reply was lost. The job's idempotency key makes the server return the original job if an
+// earlier attempt did get through.
+func submitJob(ctx context.Context, client pb.JobServiceClient, job *pb.Job) (*pb.JobResponse, error) {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		callCtx, cancel := context.WithTimeout(ctx, submitTimeout)
+		resp, err := client.SubmitJob(callCtx, job)
+		cancel()
+		if err == nil || attempt == submitAttempts || !retryableSubmitError(err) {
+			return resp, err
+		}
+		log.Printf("Submitting job failed (attempt %d/%d), retrying in %v: %v", attempt, submitAttempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// retryableSubmitError reports whether a SubmitJob error is worth retrying. Validation
+// errors come back as Unknown and would fail again.
+func retryableSubmitError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
 // printJobResult displays the result of a job execution
 func printJobResult(result JobResult) {
 	fmt.Printf("\n" + strings.Repeat("=", 60) + "\n")
@@ -248,6 +363,9 @@ func printJobResult(result JobResult) {
 		fmt.Printf("Error: %v\n", result.Error)
 	} else {
 		fmt.Printf("Status: %s\n", result.Status)
+		if r := result.Result; r != nil {
+			fmt.Printf("Result: %s\n", describeResult(r))
+		}
 		if result.Output != "" {
 			fmt.Printf("Output:\n%s\n", result.Output)
 		}
@@ -255,6 +373,50 @@ func printJobResult(result JobResult) {
 	fmt.Printf(strings.Repeat("=", 60) + "\n")
 }
 
+// describeResult summarises how a run ended and what it used.
+func describeResult(r *pb.ExecutionResult) string {
+	ended := fmt.Sprintf("exit code %d", r.ExitCode)
+	if r.Signal != "" {
+		ended = "signal " + r.Signal
+	}
+	return fmt.Sprintf("%s, wall %dms, cpu %dms user / %dms sys, max rss %d KB",
+		ended, r.WallTimeMs, r.UserCpuMs, r.SystemCpuMs, r.MaxRssKb)
+}
+
+// buildJob converts a job from the JSON file into its API representation.
+func buildJob(jobConfig JobConfig) *pb.Job {
+	job := &pb.Job{
+		Command:        jobConfig.Command,
+		CreatedAt:      time.Now().Unix(),
+		ScheduleTime:   jobConfig.ScheduleTime,
+		CronExpr:       jobConfig.CronExpr,
+		Timezone:       jobConfig.Timezone,
+		TimeoutSeconds: jobConfig.Timeout,
+		Priority:       jobConfig.Priority,
+		Queue:          jobConfig.Queue,
+		IdempotencyKey: jobConfig.IdempotencyKey,
+	}
+	if r := jobConfig.Retry; r != nil {
+		job.RetryPolicy = &pb.RetryPolicy{
+			MaxAttempts:           r.MaxAttempts,
+			InitialBackoffSeconds: r.InitialBackoffSeconds,
+			BackoffMultiplier:     r.BackoffMultiplier,
+			MaxBackoffSeconds:     r.MaxBackoffSeconds,
+			RetryableExitCodes:    r.RetryableExitCodes,
+		}
+	}
+	return job
+}
+
+// isFinalStatus reports whether a job in this status will not change again on its own.
+func isFinalStatus(status string) bool {
+	switch status {
+	case "SUCCEEDED", "FAILED", "CANCELLED", "TIMED_OUT", "SKIPPED":
+		return true
+	}
+	return false
+}
+
 func splitAndTrim(s string) []string {
 	parts := strings.Split(s, ",")
 	out := make([]string, 0, len(parts))
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	pb "distributed-task-scheduler/proto"
+)
+
+// runWatch implements `client watch <job-id>`, printing each status change as it happens.
+func runWatch(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatalf("Usage: client watch [-server=addr] <job-id>")
+	}
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+	stream, err := client.WatchJob(context.Background(), &pb.JobId{Id: fs.Arg(0)})
+	if err != nil {
+		log.Fatalf("Failed to watch job: %v", err)
+	}
+	var last *pb.JobStatus
+	for {
+		st, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Watch failed: %v", err)
+		}
+		fmt.Printf("%s  %s\n", formatUnix(st.UpdatedAt), st.Status)
+		last = st
+	}
+	if last != nil && last.Result != nil {
+		fmt.Printf("Result: %s\n", describeResult(last.Result))
+	}
+	if last != nil && last.Output != "" {
+		fmt.Printf("Output:\n%s\n", last.Output)
+	}
+}
+
+// runLogs implements `client logs [-f] <job-id>`, printing the job's output.
+func runLogs(args []string) {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	follow := fs.Bool("f", false, "Keep printing output until the job finishes")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatalf("Usage: client logs [-server=addr] [-f] <job-id>")
+	}
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+	stream, err := client.TailJobLogs(context.Background(), &pb.TailJobLogsRequest{JobId: fs.Arg(0), Follow: *follow})
+	if err != nil {
+		log.Fatalf("Failed to read logs: %v", err)
+	}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			log.Fatalf("Reading logs failed: %v", err)
+		}
+		out := os.Stdout
+		if chunk.Stream == "stderr" {
+			out = os.Stderr
+		}
+		out.Write(chunk.Data)
+	}
+}
+
+// watchJob follows a job over WatchJob and returns its status once it has settled.
+func watchJob(ctx context.Context, client pb.JobServiceClient, jobId string) (*pb.JobStatus, error) {
+	stream, err := client.WatchJob(ctx, &pb.JobId{Id: jobId})
+	if err != nil {
+		return nil, err
+	}
+	var last *pb.JobStatus
+	for {
+		st, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			if last == nil {
+				return nil, errors.New("watch ended without a status")
+			}
+			return last, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		last = st
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "distributed-task-scheduler/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const workersUsage = "Usage: client workers [list|drain|pause|resume] [flags]"
+
+// runWorkers implements `client workers <list|drain|pause|resume>`; without a subcommand
+// it lists the workers.
+func runWorkers(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		runWorkersList(args)
+		return
+	}
+	switch args[0] {
+	case "list":
+		runWorkersList(args[1:])
+	case "drain", "pause", "resume":
+		runWorkerControl(args[0], args[1:])
+	default:
+		log.Fatalf(workersUsage)
+	}
+}
+
+// runWorkersList lists the workers in the worker registry with the jobs they are running.
+func runWorkersList(args []string) {
+	fs := flag.NewFlagSet("workers list", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	all := fs.Bool("all", false, "Also list dead workers whose lease expired")
+	fs.Parse(args)
+
+	client, conn := dialClusterService(*serverFlag)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := client.ListWorkers(ctx, &pb.ListWorkersRequest{IncludeDead: *all})
+	if err != nil {
+		log.Fatalf("Failed to list workers: %v", err)
+	}
+	if len(resp.Workers) == 0 {
+		fmt.Println("No workers registered")
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATE\tHOST\tPID\tVERSION\tQUEUES\tBUSY\tSTARTED\tLAST SEEN\tRUNNING")
+	for _, w := range resp.Workers {
+		state := w.State
+		if !w.Alive {
+			state = "DEAD"
+		}
+		running := strings.Join(w.RunningJobs, ",")
+		if running == "" {
+			running = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%d/%d\t%s\t%s\t%s\n", w.Id, state, w.Host, w.Pid, w.Version,
+			strings.Join(w.Queues, ","), len(w.RunningJobs), w.Slots, formatUnix(w.StartedAt), formatUnix(w.LastSeen), running)
+	}
+	tw.Flush()
+}
+
+// runWorkerControl implements `client workers drain|pause|resume <worker-id>`. With -wait a
+// drain is followed until the worker exits.
+func runWorkerControl(action string, args []string) {
+	fs := flag.NewFlagSet("workers "+action, flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	wait := fs.Bool("wait", false, "Wait until a drained worker has exited")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatalf("Usage: client workers %s [-server=addr] [-wait] <worker-id>", action)
+	}
+	workerId := fs.Arg(0)
+
+	client, conn := dialClusterService(*serverFlag)
+	defer conn.Close()
+	req := &pb.WorkerControlRequest{WorkerId: workerId}
+	var resp *pb.WorkerControlResponse
+	var err error
+	switch action {
+	case "drain":
+		resp, err = client.DrainWorker(context.Background(), req)
+	case "pause":
+		resp, err = client.PauseWorker(context.Background(), req)
+	default:
+		resp, err = client.ResumeWorker(context.Background(), req)
+	}
+	if err != nil {
+		log.Fatalf("Failed to %s worker %s: %v", action, workerId, err)
+	}
+	fmt.Printf("Worker %s: %s\n", workerId, resp.Message)
+	if action == "drain" && *wait {
+		waitForDrain(client, workerId)
+	}
+}
+
+// waitForDrain polls the registry until the worker has left it, printing the number of
+// jobs it still runs whenever that changes.
+func waitForDrain(client pb.ClusterServiceClient, workerId string) {
+	left, draining := -1, false
+	for {
+		resp, err := client.ListWorkers(context.Background(), &pb.ListWorkersRequest{IncludeDead: true})
+		if err != nil {
+			log.Fatalf("Failed to list workers: %v", err)
+		}
+		var worker *pb.WorkerInfo
+		for _, w := range resp.Workers {
+			if w.Id == workerId {
+				worker = w
+			}
+		}
+		switch {
+		case worker == nil:
+			fmt.Printf("Worker %s drained and exited\n", workerId)
+			return
+		case !worker.Alive:
+			log.Fatalf("Worker %s died while draining", workerId)
+		case worker.State == "DRAINING":
+			draining = true
+			if len(worker.RunningJobs) != left {
+				left = len(worker.RunningJobs)
+				fmt.Printf("%s  draining, %d jobs in flight\n", time.Now().Format(time.TimeOnly), left)
+			}
+		case draining:
+			log.Fatalf("Worker %s stopped draining and is %s", workerId, worker.State)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// dialClusterService connects to the server chosen by -server, SERVERS or the default.
+func dialClusterService(serverFlag string) (pb.ClusterServiceClient, *grpc.ClientConn) {
+	addr := resolveServer(serverFlag)
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to server %s: %v", addr, err)
+	}
+	return pb.NewClusterServiceClient(conn), conn
+}
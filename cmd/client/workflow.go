@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "distributed-task-scheduler/proto"
+)
+
+const workflowUsage = "Usage: client workflow submit|status [flags]"
+
+// WorkflowFile is the JSON format accepted by `client workflow submit`.
+type WorkflowFile struct {
+	Name  string               `json:"name"`
+	Nodes []WorkflowNodeConfig `json:"nodes"`
+}
+
+// WorkflowNodeConfig is a job plus the names of the nodes it depends on; the job name is
+// the node name.
+type WorkflowNodeConfig struct {
+	JobConfig
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// runWorkflow implements `client workflow <submit|status>`.
+func runWorkflow(args []string) {
+	if len(args) == 0 {
+		log.Fatalf(workflowUsage)
+	}
+	switch args[0] {
+	case "submit":
+		runWorkflowSubmit(args[1:])
+	case "status":
+		runWorkflowStatus(args[1:])
+	default:
+		log.Fatalf(workflowUsage)
+	}
+}
+
+func runWorkflowSubmit(args []string) {
+	fs := flag.NewFlagSet("workflow submit", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	file := fs.String("file", "workflow.json", "JSON file describing the workflow")
+	wait := fs.Bool("wait", false, "Poll until the workflow has finished")
+	fs.Parse(args)
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatalf("Failed to read file %s: %v", *file, err)
+	}
+	var wfFile WorkflowFile
+	if err := json.Unmarshal(data, &wfFile); err != nil {
+		log.Fatalf("Failed to parse JSON in %s: %v", *file, err)
+	}
+
+	wf := &pb.Workflow{Name: wfFile.Name}
+	for _, node := range wfFile.Nodes {
+		wf.Nodes = append(wf.Nodes, &pb.WorkflowNode{
+			Name:      node.Name,
+			Job:       buildJob(node.JobConfig),
+			DependsOn: node.DependsOn,
+		})
+	}
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+	resp, err := client.SubmitWorkflow(context.Background(), wf)
+	if err != nil {
+		log.Fatalf("Failed to submit workflow: %v", err)
+	}
+	fmt.Printf("Workflow %s: %s\n", resp.WorkflowId, resp.Message)
+	if !resp.Success {
+		os.Exit(1)
+	}
+	if !*wait {
+		return
+	}
+
+	for {
+		st, err := client.GetWorkflowStatus(context.Background(), &pb.WorkflowId{Id: resp.WorkflowId})
+		if err != nil {
+			log.Fatalf("Failed to get workflow status: %v", err)
+		}
+		if st.Status != "RUNNING" {
+			printWorkflowStatus(st)
+			if st.Status != "SUCCEEDED" {
+				os.Exit(1)
+			}
+			return
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+func runWorkflowStatus(args []string) {
+	fs := flag.NewFlagSet("workflow status", flag.ExitOnError)
+	serverFlag := fs.String("server", "", "Server address (defaults to first of SERVERS env or localhost:50051)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatalf("Usage: client workflow status [-server=addr] <workflow-id>")
+	}
+
+	client, conn := dialJobService(*serverFlag)
+	defer conn.Close()
+	st, err := client.GetWorkflowStatus(context.Background(), &pb.WorkflowId{Id: fs.Arg(0)})
+	if err != nil {
+		log.Fatalf("Failed to get workflow status: %v", err)
+	}
+	printWorkflowStatus(st)
+}
+
+func printWorkflowStatus(st *pb.WorkflowStatus) {
+	fmt.Printf("Workflow %s (%s): %s\n\n", st.Id, st.Name, st.Status)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tJOB ID\tSTATUS\tUPDATED\tDEPENDS ON")
+	for _, n := range st.Nodes {
+		deps := "-"
+		if len(n.DependsOn) > 0 {
+			deps = strings.Join(n.DependsOn, ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", n.Name, n.JobId, n.Status, formatUnix(n.UpdatedAt), deps)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: scheduler <command> [flags]
+
+Commands:
+  standalone   run the server, leader duties and workers in one process, in memory
+  migrate      apply, revert or list database schema migrations
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "standalone":
+		runStandalone(os.Args[2:])
+	case "migrate":
+		runMigrate(os.Args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"distributed-task-scheduler/internal/db"
+)
+
+const migrateUsage = `Usage: scheduler migrate <up|down|status> [flags]
+
+  up       apply pending migrations (-to limits the version)
+  down     revert the newest applied migrations (-steps, default 1)
+  status   list migrations and whether they are applied
+`
+
+// runMigrate applies, reverts or lists the embedded schema migrations of the database at
+// -dsn (default DATABASE_URL).
+func runMigrate(args []string) {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+	fs := flag.NewFlagSet("migrate "+args[0], flag.ExitOnError)
+	dsn := fs.String("dsn", os.Getenv("DATABASE_URL"), "Postgres connection string (overrides DATABASE_URL env)")
+	to := fs.Int("to", 0, "up: apply migrations up to this version only (0 for all)")
+	steps := fs.Int("steps", 1, "down: number of migrations to revert")
+	fs.Parse(args[1:])
+
+	ctx := context.Background()
+	migrator, err := db.NewMigrator(*dsn)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx, *to)
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+		for _, m := range applied {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+	case "down":
+		if *steps < 1 {
+			log.Fatalf("-steps must be at least 1, got %d", *steps)
+		}
+		reverted, err := migrator.Down(ctx, *steps)
+		if err != nil {
+			log.Fatalf("Revert failed: %v", err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No migrations to revert")
+		}
+		for _, m := range reverted {
+			fmt.Printf("Reverted %04d_%s\n", m.Version, m.Name)
+		}
+	case "status":
+		states, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, s := range states {
+			name, applied := s.Name, "pending"
+			if name == "" {
+				name = "(unknown to this binary)"
+			}
+			if s.Applied {
+				applied = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, name, applied)
+		}
+		w.Flush()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", args[0], migrateUsage)
+		os.Exit(2)
+	}
+}
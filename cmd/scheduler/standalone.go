@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"distributed-task-scheduler/internal/db"
+	"distributed-task-scheduler/internal/queue"
+	"distributed-task-scheduler/internal/server"
+	"distributed-task-scheduler/internal/worker"
+	pb "distributed-task-scheduler/proto"
+
+	"google.golang.org/grpc"
+)
+
+// runStandalone serves the JobService from an in-memory store and queue and runs the leader
+// loops and the workers in the same process. Nothing is persisted, so it suits local demos
+// and end-to-end tests rather than production.
+func runStandalone(args []string) {
+	fs := flag.NewFlagSet("standalone", flag.ExitOnError)
+	port := fs.String("port", "50051", "Port to serve the JobService on")
+	workers := fs.Int("workers", 2, "Number of workers to run")
+	slots := fs.Int("slots", 1, "Number of jobs each worker runs concurrently")
+	queueSpec := fs.String("queues", "default", "Queues the workers take jobs from as name[:weight],...")
+	idempotencyWindow := fs.Duration("idempotency-window", 24*time.Hour, "How long an idempotency key maps to its job")
+	fs.Parse(args)
+
+	queues, err := worker.ParseQueueWeights(*queueSpec)
+	if err != nil {
+		log.Fatalf("Invalid queue subscriptions: %v", err)
+	}
+	if *workers < 1 {
+		log.Fatalf("Standalone mode needs at least one worker, got %d", *workers)
+	}
+
+	lis, err := net.Listen("tcp", ":"+*port)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	store := db.NewMemoryStore()
+	queueMgr := queue.NewMemoryQueue()
+	jobServer := server.NewJobServerWith(store, queueMgr, *idempotencyWindow)
+	defer jobServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The only server is always the leader
+	go jobServer.RunOutboxRelay(ctx)
+	go jobServer.StartLeaderLoops(ctx, "standalone", 1)
+
+	var pool []*worker.Worker
+	var wg sync.WaitGroup
+	for i := 1; i <= *workers; i++ {
+		w, err := worker.NewWorkerWith(fmt.Sprintf("standalone-%d", i), store, queueMgr, *slots, queues)
+		if err != nil {
+			log.Fatalf("Failed to create worker: %v", err)
+		}
+		pool = append(pool, w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.Start(ctx); err != nil && err != context.Canceled {
+				log.Printf("Worker failed: %v", err)
+			}
+		}()
+	}
+
+	s := grpc.NewServer()
+	pb.RegisterJobServiceServer(s, jobServer)
+	pb.RegisterClusterServiceServer(s, server.NewClusterServer(jobServer, "standalone", nil))
+
+	// The first signal stops accepting requests and drains in-flight jobs, a second one kills them
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigChan
+		log.Printf("Received signal %v, finishing in-flight jobs (signal again to kill them)...", sig)
+		cancel()
+		s.Stop()
+		sig = <-sigChan
+		log.Printf("Received signal %v, killing in-flight jobs...", sig)
+		for _, w := range pool {
+			w.Kill()
+		}
+	}()
+
+	log.Printf("Standalone scheduler listening at %v with %d workers x %d slots on %v", lis.Addr(), *workers, *slots, queues)
+	if err := s.Serve(lis); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+	wg.Wait()
+}
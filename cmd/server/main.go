@@ -5,10 +5,12 @@ import (
 	"log"
 	"net"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"distributed-task-scheduler/internal/coord"
+	"distributed-task-scheduler/internal/queue"
 	"distributed-task-scheduler/internal/server"
 	pb "distributed-task-scheduler/proto"
 
@@ -31,15 +33,59 @@ func main() {
 	if redisAddr == "" {
 		redisAddr = "localhost:6379"
 	}
-	jobServer, err := server.NewJobServer(dsn, redisAddr)
+	idempotencyWindow := 24 * time.Hour
+	if v := os.Getenv("IDEMPOTENCY_WINDOW"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			idempotencyWindow = d
+		}
+	}
+	// QUEUE_BACKEND=postgres keeps the queues in Postgres, so no Redis is needed;
+	// QUEUE_BACKEND=streams keeps them in Redis Streams
+	queueBackend := os.Getenv("QUEUE_BACKEND")
+	if queueBackend == "" {
+		queueBackend = queue.BACKEND_REDIS
+	}
+	// AUTO_MIGRATE=true applies pending schema migrations on startup; without it the
+	// server refuses to start until `scheduler migrate up` has run
+	autoMigrate := false
+	if v := os.Getenv("AUTO_MIGRATE"); v != "" {
+		if autoMigrate, err = strconv.ParseBool(v); err != nil {
+			log.Fatalf("invalid AUTO_MIGRATE %q: %v", v, err)
+		}
+	}
+	jobServer, err := server.NewJobServer(dsn, queueBackend, redisAddr, idempotencyWindow, autoMigrate)
 	if err != nil {
 		log.Fatalf("failed to create job server: %v", err)
 	}
 	defer jobServer.Close()
 
-	// Leader election (optional)
-	if eps := os.Getenv("ETCD_ENDPOINTS"); eps != "" {
-		endpoints := strings.Split(eps, ",")
+	// Every server relays outbox entries to Redis, with or without leader election
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go jobServer.RunOutboxRelay(relayCtx)
+
+	// Leader election: LEADER_ELECTION=etcd, postgres or none. The default is etcd when
+	// ETCD_ENDPOINTS is set and postgres otherwise, so cron and delayed jobs always fire.
+	electionBackend := os.Getenv("LEADER_ELECTION")
+	if electionBackend == "" {
+		electionBackend = coord.COORD_POSTGRES
+		if os.Getenv("ETCD_ENDPOINTS") != "" {
+			electionBackend = coord.COORD_ETCD
+		}
+	}
+	leaseTTL := 10 * time.Second
+	if v := os.Getenv("LEASE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			leaseTTL = d
+		}
+	}
+	var leader coord.Elector
+	switch electionBackend {
+	case coord.COORD_ETCD:
+		if os.Getenv("ETCD_ENDPOINTS") == "" {
+			log.Fatalf("LEADER_ELECTION=etcd needs ETCD_ENDPOINTS")
+		}
+		endpoints := strings.Split(os.Getenv("ETCD_ENDPOINTS"), ",")
 		ns := os.Getenv("ELECTION_NAMESPACE")
 		if ns == "" {
 			ns = "/scheduler/v1"
@@ -48,30 +94,35 @@ func main() {
 		if name == "" {
 			name = "leader"
 		}
-		leaseTTL := 10 * time.Second
-		if v := os.Getenv("LEASE_TTL"); v != "" {
-			if d, err := time.ParseDuration(v); err == nil {
-				leaseTTL = d
-			}
-		}
-		leader := coord.NewEtcdLeader(endpoints, ns, name, leaseTTL)
+		leader = coord.NewEtcdLeader(endpoints, ns, name, leaseTTL)
+		log.Printf("Leader election enabled with etcd endpoints=%v namespace=%s key=%s ttl=%s", endpoints, ns, name, leaseTTL)
+	case coord.COORD_POSTGRES:
+		leader = coord.NewPostgresLeader(dsn, leaseTTL)
+		log.Printf("Leader election enabled with a Postgres advisory lock, ttl=%s", leaseTTL)
+	case "none":
+		electionBackend = ""
+		log.Printf("Leader election disabled (LEADER_ELECTION=none). Running without leader-only duties.")
+	default:
+		log.Fatalf("unknown LEADER_ELECTION %q (want etcd, postgres or none)", electionBackend)
+	}
+	var election server.Election
+	if leader != nil {
+		election = leader
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 		go func() {
 			_ = leader.Run(ctx, coord.LeaderCallbacks{
-				OnStartedLeading: func(lctx context.Context) {
-					jobServer.StartLeaderLoops(lctx)
+				OnStartedLeading: func(lctx context.Context, epoch int64) {
+					jobServer.StartLeaderLoops(lctx, electionBackend, epoch)
 				},
 			})
 		}()
-		log.Printf("Leader election enabled with endpoints=%v namespace=%s key=%s ttl=%s", endpoints, ns, name, leaseTTL)
-	} else {
-		log.Printf("Leader election disabled (ETCD_ENDPOINTS not set). Running without leader-only duties.")
 	}
 
 	// Create gRPC server
 	s := grpc.NewServer()
 	pb.RegisterJobServiceServer(s, jobServer)
+	pb.RegisterClusterServiceServer(s, server.NewClusterServer(jobServer, electionBackend, election))
 
 	log.Printf("Server listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	pb "distributed-task-scheduler/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type maintenanceScan struct {
+	At               int64 `json:"at"`
+	StaleFailed      int64 `json:"staleFailed"`
+	DelayedPromoted  int32 `json:"delayedPromoted"`
+	DueEnqueued      int32 `json:"dueEnqueued"`
+	OrphansReclaimed int32 `json:"orphansReclaimed"`
+	WorkflowSkipped  int64 `json:"workflowSkipped"`
+	WorkflowReleased int32 `json:"workflowReleased"`
+}
+
+type nodeStatus struct {
+	Server              string           `json:"server"`
+	NodeID              string           `json:"nodeId"`
+	Role                string           `json:"role"`
+	Election            string           `json:"election"`
+	LeaderID            string           `json:"leaderId"`
+	LeaderEpoch         int64            `json:"leaderEpoch"`
+	LeaderChangedAt     int64            `json:"leaderChangedAt"`
+	LeaseTTLSeconds     int64            `json:"leaseTtlSeconds"`
+	LeaderDutiesRunning bool             `json:"leaderDutiesRunning"`
+	LastScan            *maintenanceScan `json:"lastScan,omitempty"`
+	Error               string           `json:"error,omitempty"`
+}
+
+// handleCluster asks every configured server for its cluster status, in parallel.
+func handleCluster(w http.ResponseWriter, r *http.Request) {
+	servers := resolveServers()
+	nodes := make([]nodeStatus, len(servers))
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nodes[i] = fetchNodeStatus(server)
+		}()
+	}
+	wg.Wait()
+	writeJSON(w, nodes)
+}
+
+func fetchNodeStatus(server string) nodeStatus {
+	node := nodeStatus{Server: server}
+	conn, err := grpc.Dial(server, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		node.Error = fmt.Sprintf("dial error: %v", err)
+		return node
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	st, err := pb.NewClusterServiceClient(conn).GetClusterStatus(ctx, &pb.GetClusterStatusRequest{})
+	if err != nil {
+		node.Error = fmt.Sprintf("status error: %v", err)
+		return node
+	}
+	node.NodeID = st.NodeId
+	node.Role = st.Role
+	node.Election = st.Election
+	node.LeaderID = st.LeaderId
+	node.LeaderEpoch = st.LeaderEpoch
+	node.LeaderChangedAt = st.LeaderChangedAt
+	node.LeaseTTLSeconds = st.LeaseTtlSeconds
+	node.LeaderDutiesRunning = st.LeaderDutiesRunning
+	if sc := st.LastScan; sc != nil {
+		node.LastScan = &maintenanceScan{
+			At:               sc.At,
+			StaleFailed:      sc.StaleFailed,
+			DelayedPromoted:  sc.DelayedPromoted,
+			DueEnqueued:      sc.DueEnqueued,
+			OrphansReclaimed: sc.OrphansReclaimed,
+			WorkflowSkipped:  sc.WorkflowSkipped,
+			WorkflowReleased: sc.WorkflowReleased,
+		}
+	}
+	return node
+}
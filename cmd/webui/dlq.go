@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "distributed-task-scheduler/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type deadLetter struct {
+	ID         string `json:"id"`
+	Queue      string `json:"queue"`
+	Command    string `json:"command"`
+	Status     string `json:"status"`
+	LastOutput string `json:"lastOutput"`
+	Attempts   int32  `json:"attempts"`
+	FailedAt   int64  `json:"failedAt"`
+}
+
+type deadLettersResponse struct {
+	DeadLetters []deadLetter `json:"deadLetters"`
+	Total       int32        `json:"total"`
+	Error       string       `json:"error,omitempty"`
+}
+
+type replayRequest struct {
+	Server string `json:"server"`
+	JobID  string `json:"jobId"`
+}
+
+type replayResponse struct {
+	Message string `json:"message"`
+	Error   string `json:"error,omitempty"`
+}
+
+type purgeRequest struct {
+	Server          string   `json:"server"`
+	Queue           string   `json:"queue"`
+	IDs             []string `json:"ids"`
+	OlderThanHours  float64  `json:"olderThanHours"`
+	CommandContains string   `json:"commandContains"`
+	All             bool     `json:"all"`
+}
+
+type purgeResponse struct {
+	Purged int32  `json:"purged"`
+	Error  string `json:"error,omitempty"`
+}
+
+func handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	server := r.URL.Query().Get("server")
+	if strings.TrimSpace(server) == "" {
+		http.Error(w, "server query param is required", http.StatusBadRequest)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	conn, err := grpc.Dial(server, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		writeJSON(w, deadLettersResponse{Error: fmt.Sprintf("dial error: %v", err)})
+		return
+	}
+	defer conn.Close()
+	client := pb.NewJobServiceClient(conn)
+	resp, err := client.ListDeadLetters(context.Background(), &pb.ListDeadLettersRequest{
+		Queue: r.URL.Query().Get("queue"),
+		Limit: int32(limit),
+	})
+	if err != nil {
+		writeJSON(w, deadLettersResponse{Error: fmt.Sprintf("list error: %v", err)})
+		return
+	}
+	out := deadLettersResponse{DeadLetters: []deadLetter{}, Total: resp.Total}
+	for _, d := range resp.DeadLetters {
+		out.DeadLetters = append(out.DeadLetters, deadLetter{
+			ID:         d.Id,
+			Queue:      d.Queue,
+			Command:    d.Command,
+			Status:     d.Status,
+			LastOutput: d.LastOutput,
+			Attempts:   d.Attempts,
+			FailedAt:   d.FailedAt,
+		})
+	}
+	writeJSON(w, out)
+}
+
+func handleReplay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req replayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Server) == "" || strings.TrimSpace(req.JobID) == "" {
+		http.Error(w, "server and jobId are required", http.StatusBadRequest)
+		return
+	}
+	conn, err := grpc.Dial(req.Server, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		writeJSON(w, replayResponse{Error: fmt.Sprintf("dial error: %v", err)})
+		return
+	}
+	defer conn.Close()
+	client := pb.NewJobServiceClient(conn)
+	resp, err := client.ReplayDeadLetter(context.Background(), &pb.JobId{Id: req.JobID})
+	if err != nil {
+		writeJSON(w, replayResponse{Error: fmt.Sprintf("replay error: %v", err)})
+		return
+	}
+	if !resp.Success {
+		writeJSON(w, replayResponse{Error: resp.Message})
+		return
+	}
+	writeJSON(w, replayResponse{Message: resp.Message})
+}
+
+func handlePurge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req purgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Server) == "" {
+		http.Error(w, "server is required", http.StatusBadRequest)
+		return
+	}
+	conn, err := grpc.Dial(req.Server, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		writeJSON(w, purgeResponse{Error: fmt.Sprintf("dial error: %v", err)})
+		return
+	}
+	defer conn.Close()
+	client := pb.NewJobServiceClient(conn)
+	purge := &pb.PurgeDeadLettersRequest{
+		Queue:           req.Queue,
+		Ids:             req.IDs,
+		CommandContains: req.CommandContains,
+		All:             req.All,
+	}
+	if req.OlderThanHours > 0 {
+		purge.FailedBefore = time.Now().Add(-time.Duration(req.OlderThanHours * float64(time.Hour))).Unix()
+	}
+	resp, err := client.PurgeDeadLetters(context.Background(), purge)
+	if err != nil {
+		writeJSON(w, purgeResponse{Error: fmt.Sprintf("purge error: %v", err)})
+		return
+	}
+	writeJSON(w, purgeResponse{Purged: resp.Purged})
+}
@@ -33,9 +33,10 @@ type submitResponse struct {
 }
 
 type statusResponse struct {
-	Status string `json:"status"`
-	Output string `json:"output"`
-	Error  string `json:"error,omitempty"`
+	Status string              `json:"status"`
+	Output string              `json:"output"`
+	Result *pb.ExecutionResult `json:"result,omitempty"`
+	Error  string              `json:"error,omitempty"`
 }
 
 func main() {
@@ -47,26 +48,40 @@ func main() {
 	// Static files
 	sub, _ := fs.Sub(staticFS, "static")
 	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.FS(sub))))
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		// serve embedded index.html
-		b, err := staticFS.ReadFile(filepath.Join("static", "index.html"))
-		if err != nil {
-			http.Error(w, "index not found", http.StatusInternalServerError)
-			return
-		}
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		_, _ = w.Write(b)
-	})
+	http.HandleFunc("/", servePage("index.html"))
+	http.HandleFunc("/dlq", servePage("dlq.html"))
+	http.HandleFunc("/cluster", servePage("cluster.html"))
+	http.HandleFunc("/workers", servePage("workers.html"))
 
 	// API endpoints
 	http.HandleFunc("/servers", handleServers)
 	http.HandleFunc("/submit", handleSubmit)
 	http.HandleFunc("/status", handleStatus)
+	http.HandleFunc("/watch", handleWatch)
+	http.HandleFunc("/deadletters", handleDeadLetters)
+	http.HandleFunc("/deadletters/replay", handleReplay)
+	http.HandleFunc("/deadletters/purge", handlePurge)
+	http.HandleFunc("/cluster/status", handleCluster)
+	http.HandleFunc("/workers/list", handleWorkers)
+	http.HandleFunc("/workers/control", handleWorkerControl)
 
 	log.Printf("Web UI listening on %s", addr)
 	log.Fatal(http.ListenAndServe(addr, nil))
 }
 
+// servePage serves an embedded HTML page.
+func servePage(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, err := staticFS.ReadFile(filepath.Join("static", name))
+		if err != nil {
+			http.Error(w, "page not found", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(b)
+	}
+}
+
 func handleServers(w http.ResponseWriter, r *http.Request) {
 	servers := resolveServers()
 	writeJSON(w, servers)
@@ -123,7 +138,7 @@ func handleStatus(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, statusResponse{Error: fmt.Sprintf("status error: %v", err)})
 		return
 	}
-	writeJSON(w, statusResponse{Status: st.Status, Output: st.Output})
+	writeJSON(w, statusResponse{Status: st.Status, Output: st.Output, Result: st.Result})
 }
 
 func resolveServers() []string {
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	pb "distributed-task-scheduler/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type sseEvent struct {
+	name string
+	data interface{}
+}
+
+type logEvent struct {
+	Stream string `json:"stream"`
+	Data   string `json:"data"`
+}
+
+// handleWatch streams a job's status changes ("status" events) and output ("log" events)
+// to the browser as server-sent events, finishing with an "end" event.
+func handleWatch(w http.ResponseWriter, r *http.Request) {
+	jobID := r.URL.Query().Get("jobId")
+	server := r.URL.Query().Get("server")
+	if strings.TrimSpace(jobID) == "" || strings.TrimSpace(server) == "" {
+		http.Error(w, "jobId and server query params are required", http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, err := grpc.Dial(server, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("dial error: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer conn.Close()
+	client := pb.NewJobServiceClient(conn)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	events := make(chan sseEvent)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		forwardStatus(ctx, client, jobID, events)
+	}()
+	go func() {
+		defer wg.Done()
+		forwardLogs(ctx, client, jobID, events)
+	}()
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	for ev := range events {
+		writeEvent(w, ev)
+		flusher.Flush()
+	}
+	writeEvent(w, sseEvent{name: "end", data: struct{}{}})
+	flusher.Flush()
+}
+
+func forwardStatus(ctx context.Context, client pb.JobServiceClient, jobID string, events chan<- sseEvent) {
+	stream, err := client.WatchJob(ctx, &pb.JobId{Id: jobID})
+	if err != nil {
+		sendEvent(ctx, events, sseEvent{name: "failure", data: statusResponse{Error: fmt.Sprintf("watch error: %v", err)}})
+		return
+	}
+	for {
+		st, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			sendEvent(ctx, events, sseEvent{name: "failure", data: statusResponse{Error: fmt.Sprintf("watch error: %v", err)}})
+			return
+		}
+		if !sendEvent(ctx, events, sseEvent{name: "status", data: statusResponse{Status: st.Status, Output: st.Output, Result: st.Result}}) {
+			return
+		}
+	}
+}
+
+func forwardLogs(ctx context.Context, client pb.JobServiceClient, jobID string, events chan<- sseEvent) {
+	stream, err := client.TailJobLogs(ctx, &pb.TailJobLogsRequest{JobId: jobID, Follow: true})
+	if err != nil {
+		return
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return
+		}
+		if !sendEvent(ctx, events, sseEvent{name: "log", data: logEvent{Stream: chunk.Stream, Data: string(chunk.Data)}}) {
+			return
+		}
+	}
+}
+
+func sendEvent(ctx context.Context, events chan<- sseEvent, ev sseEvent) bool {
+	select {
+	case events <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func writeEvent(w io.Writer, ev sseEvent) {
+	b, _ := json.Marshal(ev.data)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.name, b)
+}
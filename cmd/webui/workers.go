@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "distributed-task-scheduler/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type workerInfo struct {
+	ID          string   `json:"id"`
+	Host        string   `json:"host"`
+	PID         int32    `json:"pid"`
+	Version     string   `json:"version"`
+	Queues      []string `json:"queues"`
+	Slots       int32    `json:"slots"`
+	RunningJobs []string `json:"runningJobs"`
+	StartedAt   int64    `json:"startedAt"`
+	LastSeen    int64    `json:"lastSeen"`
+	Alive       bool     `json:"alive"`
+	State       string   `json:"state"`
+}
+
+type workersResponse struct {
+	Server  string       `json:"server"`
+	Workers []workerInfo `json:"workers"`
+	Error   string       `json:"error,omitempty"`
+}
+
+type workerControlRequest struct {
+	Server   string `json:"server"`
+	WorkerID string `json:"workerId"`
+	Action   string `json:"action"` // drain, pause or resume
+}
+
+type workerControlResponse struct {
+	Message string `json:"message"`
+	Error   string `json:"error,omitempty"`
+}
+
+// handleWorkers lists the worker registry, including dead workers. Every server reads the
+// same registry, so the first one that answers is used.
+func handleWorkers(w http.ResponseWriter, r *http.Request) {
+	out := workersResponse{Workers: []workerInfo{}}
+	for _, server := range resolveServers() {
+		resp, err := listWorkers(server)
+		if err != nil {
+			out.Error = fmt.Sprintf("%s: %v", server, err)
+			continue
+		}
+		out.Server, out.Error = server, ""
+		for _, wi := range resp.Workers {
+			out.Workers = append(out.Workers, workerInfo{
+				ID:          wi.Id,
+				Host:        wi.Host,
+				PID:         wi.Pid,
+				Version:     wi.Version,
+				Queues:      wi.Queues,
+				Slots:       wi.Slots,
+				RunningJobs: wi.RunningJobs,
+				StartedAt:   wi.StartedAt,
+				LastSeen:    wi.LastSeen,
+				Alive:       wi.Alive,
+				State:       wi.State,
+			})
+		}
+		break
+	}
+	writeJSON(w, out)
+}
+
+func listWorkers(server string) (*pb.ListWorkersResponse, error) {
+	conn, err := grpc.Dial(server, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return pb.NewClusterServiceClient(conn).ListWorkers(ctx, &pb.ListWorkersRequest{IncludeDead: true})
+}
+
+func handleWorkerControl(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req workerControlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Server) == "" || strings.TrimSpace(req.WorkerID) == "" {
+		http.Error(w, "server and workerId are required", http.StatusBadRequest)
+		return
+	}
+	conn, err := grpc.Dial(req.Server, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		writeJSON(w, workerControlResponse{Error: fmt.Sprintf("dial error: %v", err)})
+		return
+	}
+	defer conn.Close()
+	client := pb.NewClusterServiceClient(conn)
+	ctrl := &pb.WorkerControlRequest{WorkerId: req.WorkerID}
+	var resp *pb.WorkerControlResponse
+	switch req.Action {
+	case "drain":
+		resp, err = client.DrainWorker(context.Background(), ctrl)
+	case "pause":
+		resp, err = client.PauseWorker(context.Background(), ctrl)
+	case "resume":
+		resp, err = client.ResumeWorker(context.Background(), ctrl)
+	default:
+		http.Error(w, "action must be drain, pause or resume", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeJSON(w, workerControlResponse{Error: fmt.Sprintf("%s error: %v", req.Action, err)})
+		return
+	}
+	writeJSON(w, workerControlResponse{Message: resp.Message})
+}
@@ -2,17 +2,47 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
+	"distributed-task-scheduler/internal/queue"
 	"distributed-task-scheduler/internal/worker"
 
 	"github.com/google/uuid"
 )
 
 func main() {
+	// Concurrency: -slots flag, then WORKER_SLOTS env, default 1
+	defaultSlots := 1
+	if v := os.Getenv("WORKER_SLOTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			defaultSlots = n
+		}
+	}
+	slots := flag.Int("slots", defaultSlots, "Number of jobs to run concurrently (overrides WORKER_SLOTS env)")
+	// Queue subscriptions: -queues flag, then WORKER_QUEUES env, default "default"
+	defaultQueues := os.Getenv("WORKER_QUEUES")
+	if defaultQueues == "" {
+		defaultQueues = "default"
+	}
+	queueSpec := flag.String("queues", defaultQueues, "Queues to take jobs from as name[:weight],... (overrides WORKER_QUEUES env)")
+	// Queue backend: -queue-backend flag, then QUEUE_BACKEND env, default redis
+	defaultBackend := os.Getenv("QUEUE_BACKEND")
+	if defaultBackend == "" {
+		defaultBackend = queue.BACKEND_REDIS
+	}
+	queueBackend := flag.String("queue-backend", defaultBackend, "Queue backend: redis, postgres or streams (overrides QUEUE_BACKEND env)")
+	flag.Parse()
+
+	queues, err := worker.ParseQueueWeights(*queueSpec)
+	if err != nil {
+		log.Fatalf("Invalid queue subscriptions: %v", err)
+	}
+
 	// Generate unique worker ID
 	workerId := uuid.New().String()
 
@@ -22,7 +52,7 @@ func main() {
 	if redisAddr == "" {
 		redisAddr = "localhost:6379"
 	}
-	w, err := worker.NewWorker(workerId, dsn, redisAddr)
+	w, err := worker.NewWorker(workerId, dsn, *queueBackend, redisAddr, *slots, queues)
 	if err != nil {
 		log.Fatalf("Failed to create worker: %v", err)
 	}
@@ -32,18 +62,21 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Handle shutdown signals
+	// Handle shutdown signals: the first one drains in-flight jobs, a second one kills them
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		sig := <-sigChan
-		log.Printf("Received signal %v, shutting down...", sig)
+		log.Printf("Received signal %v, finishing in-flight jobs (signal again to kill them)...", sig)
 		cancel()
+		sig = <-sigChan
+		log.Printf("Received signal %v, killing in-flight jobs...", sig)
+		w.Kill()
 	}()
 
 	// Start worker
-	log.Printf("Starting worker %s...", workerId)
+	log.Printf("Starting worker %s (version %s)...", workerId, worker.Version)
 	if err := w.Start(ctx); err != nil && err != context.Canceled {
 		log.Fatalf("Worker failed: %v", err)
 	}
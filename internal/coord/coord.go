@@ -0,0 +1,85 @@
+package coord
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Election backends, as chosen with LEADER_ELECTION. The leader fence records which one
+// registered its epoch.
+const (
+	COORD_ETCD     = "etcd"
+	COORD_POSTGRES = "postgres"
+)
+
+// Elector runs a leader election; EtcdLeader and PostgresLeader implement it.
+type Elector interface {
+	// Run campaigns for leadership until ctx is done, calling back while this node leads.
+	Run(ctx context.Context, cb LeaderCallbacks) error
+	// Status returns the election state as last observed by Run.
+	Status() LeaderStatus
+}
+
+// LeaderCallbacks defines actions to perform when gaining or losing leadership.
+type LeaderCallbacks struct {
+	// OnStartedLeading is called when leadership is acquired. It is given a context that
+	// will be canceled when leadership is lost or Run is stopped, and the epoch of this
+	// leadership term, which is greater than that of every earlier term. Pass the epoch
+	// along with leader-only writes as a fencing token: a paused leader may still be
+	// running after its context was canceled and a new leader was elected.
+	OnStartedLeading func(ctx context.Context, epoch int64)
+}
+
+// LeaderStatus is the state of the election as last seen by this node.
+type LeaderStatus struct {
+	// Identity is this node's candidate value, hostname-pid.
+	Identity string
+	// Leader is the identity of the current leader, empty while unknown or vacant.
+	Leader   string
+	IsLeader bool
+	// Epoch is the epoch of the current leader's term.
+	Epoch int64
+	// ChangedAt is when this node saw the leader change last.
+	ChangedAt time.Time
+	LeaseTTL  time.Duration
+}
+
+// tracker keeps the LeaderStatus of an Elector.
+type tracker struct {
+	mu     sync.Mutex
+	status LeaderStatus
+}
+
+func newTracker(identity string, leaseTTL time.Duration) tracker {
+	return tracker{status: LeaderStatus{Identity: identity, LeaseTTL: leaseTTL}}
+}
+
+// Status returns the election state as last observed by Run.
+func (t *tracker) Status() LeaderStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// setLeader records leader and epoch as the current term, bumping ChangedAt if they differ
+// from what was recorded before.
+func (t *tracker) setLeader(leader string, epoch int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.status.Leader == leader && t.status.Epoch == epoch {
+		return
+	}
+	t.status.Leader = leader
+	t.status.Epoch = epoch
+	t.status.IsLeader = leader == t.status.Identity && leader != ""
+	t.status.ChangedAt = time.Now()
+}
+
+// Identity names this process in elections and cluster status: hostname-pid.
+func Identity() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
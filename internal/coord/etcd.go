@@ -3,7 +3,6 @@ package coord
 import (
 	"context"
 	"fmt"
-	"os"
 	"path"
 	"time"
 
@@ -11,27 +10,38 @@ import (
 	"go.etcd.io/etcd/client/v3/concurrency"
 )
 
-// LeaderCallbacks defines actions to perform when gaining or losing leadership.
-type LeaderCallbacks struct {
-	// OnStartedLeading is called when leadership is acquired. It is given a context that
-	// will be canceled when leadership is lost or Run is stopped.
-	OnStartedLeading func(ctx context.Context)
-}
-
 // EtcdLeader coordinates leader election using etcd.
 type EtcdLeader struct {
 	endpoints    []string
 	namespace    string
 	electionName string
 	leaseTTL     time.Duration
+	identity     string
+
+	tracker
 }
 
 func NewEtcdLeader(endpoints []string, namespace, electionName string, leaseTTL time.Duration) *EtcdLeader {
+	ident := Identity()
 	return &EtcdLeader{
 		endpoints:    endpoints,
 		namespace:    namespace,
 		electionName: electionName,
 		leaseTTL:     leaseTTL,
+		identity:     ident,
+		tracker:      newTracker(ident, leaseTTL),
+	}
+}
+
+// observe follows the election key until ctx is done, recording every leader change.
+func (e *EtcdLeader) observe(ctx context.Context, election *concurrency.Election) {
+	for resp := range election.Observe(ctx) {
+		if len(resp.Kvs) == 0 {
+			e.setLeader("", 0)
+			continue
+		}
+		kv := resp.Kvs[0]
+		e.setLeader(string(kv.Value), kv.CreateRevision)
 	}
 }
 
@@ -67,10 +77,12 @@ func (e *EtcdLeader) Run(ctx context.Context, cb LeaderCallbacks) error {
 		}
 
 		election := concurrency.NewElection(sess, path.Join(e.namespace, e.electionName))
+		observeCtx, stopObserving := context.WithCancel(ctx)
+		go e.observe(observeCtx, election)
 
 		// campaign to become leader
-		ident := leaderIdentity()
-		if err := election.Campaign(ctx, ident); err != nil {
+		if err := election.Campaign(ctx, e.identity); err != nil {
+			stopObserving()
 			sess.Close()
 			if ctx.Err() != nil {
 				return ctx.Err()
@@ -79,36 +91,51 @@ func (e *EtcdLeader) Run(ctx context.Context, cb LeaderCallbacks) error {
 			continue
 		}
 
-		// became leader; run callback with a context tied to session
+		// became leader; run callback with a context tied to session. The revision that
+		// created our election key orders the terms, so it serves as the epoch.
 		leaderCtx, cancel := context.WithCancel(ctx)
+		epoch := election.Rev()
+		e.setLeader(e.identity, epoch)
 		done := make(chan struct{})
-		if cb.OnStartedLeading != nil {
-			go func() {
-				cb.OnStartedLeading(leaderCtx)
-				close(done)
-			}()
-		}
+		go func() {
+			if cb.OnStartedLeading != nil {
+				cb.OnStartedLeading(leaderCtx, epoch)
+			}
+			close(done)
+		}()
 
-		// wait for session end or context cancel
+		// wait for session end, context cancel or the callback giving up
 		select {
 		case <-ctx.Done():
 			cancel()
 			_ = election.Resign(context.Background())
 			_ = sess.Close()
+			stopObserving()
+			e.setLeader("", 0)
 			<-done
 			return ctx.Err()
 		case <-sess.Done():
 			cancel()
 			_ = election.Resign(context.Background())
 			_ = sess.Close()
+			stopObserving()
+			e.setLeader("", 0)
 			<-done
 			// loop to re-enter election
 			continue
+		case <-done:
+			// Hand leadership to another candidate rather than holding it idle
+			cancel()
+			_ = election.Resign(context.Background())
+			_ = sess.Close()
+			stopObserving()
+			e.setLeader("", 0)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(2 * time.Second):
+				continue
+			}
 		}
 	}
 }
-
-func leaderIdentity() string {
-	host, _ := os.Hostname()
-	return fmt.Sprintf("%s-%d", host, os.Getpid())
-}
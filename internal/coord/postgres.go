@@ -0,0 +1,174 @@
+package coord
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PG_LEADER_LOCK_KEY is the advisory lock held by the leader for as long as it leads.
+const PG_LEADER_LOCK_KEY int64 = 0x6c6561646572 // "leader"
+
+// errLeadershipEnded stops a term whose callback returned while the node still held the lock.
+var errLeadershipEnded = errors.New("leader callback returned")
+
+// PostgresLeader coordinates leader election with a Postgres advisory lock, for deployments
+// without etcd. The lock is taken with pg_try_advisory_lock on a dedicated connection and
+// released by Postgres when that connection ends, so a leader that dies or loses the
+// connection steps down on its own.
+type PostgresLeader struct {
+	dsn      string
+	leaseTTL time.Duration
+	identity string
+
+	tracker
+}
+
+// NewPostgresLeader campaigns on the database at dsn. Candidates retry the lock, and the
+// leader checks its connection, every leaseTTL/3; a check that takes longer than leaseTTL
+// counts as a lost connection.
+func NewPostgresLeader(dsn string, leaseTTL time.Duration) *PostgresLeader {
+	ident := Identity()
+	return &PostgresLeader{
+		dsn:      dsn,
+		leaseTTL: leaseTTL,
+		identity: ident,
+		tracker:  newTracker(ident, leaseTTL),
+	}
+}
+
+// Run performs leader election and invokes callbacks while leader.
+func (p *PostgresLeader) Run(ctx context.Context, cb LeaderCallbacks) error {
+	config, err := pgx.ParseConfig(p.dsn)
+	if err != nil {
+		return err
+	}
+	// Other candidates read the leader's identity from pg_stat_activity
+	config.RuntimeParams["application_name"] = p.identity
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		conn, err := pgx.ConnectConfig(ctx, config)
+		if err == nil {
+			err = p.campaign(ctx, conn, cb)
+			conn.Close(context.Background())
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil && err != errLeadershipEnded {
+			log.Printf("Postgres leader election: %v", err)
+		}
+		p.setLeader("", 0)
+		// brief backoff before reconnecting
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// campaign tries the lock on conn until it gets it, then leads until the term ends. It
+// returns the error that ended the term or broke conn.
+func (p *PostgresLeader) campaign(ctx context.Context, conn *pgx.Conn, cb LeaderCallbacks) error {
+	ticker := time.NewTicker(p.leaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		var locked bool
+		if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, PG_LEADER_LOCK_KEY).Scan(&locked); err != nil {
+			return err
+		}
+		if locked {
+			return p.lead(ctx, conn, cb)
+		}
+		if err := p.observe(ctx, conn); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead runs the callback while conn holds the lock and answers pings in time.
+func (p *PostgresLeader) lead(ctx context.Context, conn *pgx.Conn, cb LeaderCallbacks) error {
+	defer func() {
+		// Closing conn releases the lock too, should this fail
+		unlockCtx, cancel := context.WithTimeout(context.Background(), p.leaseTTL)
+		defer cancel()
+		_, _ = conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, PG_LEADER_LOCK_KEY)
+	}()
+
+	// Reserve the epoch one past the newest one while holding the lock, so no other term can
+	// take it. AdvanceLeaderEpoch accepts it again from this identity only. The fence table
+	// is created by the migrations.
+	var epoch int64
+	if err := conn.QueryRow(ctx,
+		`INSERT INTO leader_fence (id, epoch, holder, coordinator) VALUES (1, 1, $1, $2)
+		 ON CONFLICT (id) DO UPDATE SET epoch = leader_fence.epoch + 1, holder = EXCLUDED.holder,
+		   coordinator = EXCLUDED.coordinator, updated_at = now()
+		 RETURNING epoch`, p.identity, COORD_POSTGRES,
+	).Scan(&epoch); err != nil {
+		return err
+	}
+	p.setLeader(p.identity, epoch)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		if cb.OnStartedLeading != nil {
+			cb.OnStartedLeading(leaderCtx, epoch)
+		}
+		close(done)
+	}()
+
+	ticker := time.NewTicker(p.leaseTTL / 3)
+	defer ticker.Stop()
+	var err error
+	for err == nil {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-done:
+			// Hand leadership to another candidate rather than holding the lock idle
+			err = errLeadershipEnded
+		case <-ticker.C:
+			pingCtx, cancelPing := context.WithTimeout(ctx, p.leaseTTL)
+			err = conn.Ping(pingCtx)
+			cancelPing()
+		}
+	}
+	cancel()
+	<-done
+	return err
+}
+
+// observe records the candidate holding the lock, read from pg_locks, and the epoch it
+// registered.
+func (p *PostgresLeader) observe(ctx context.Context, conn *pgx.Conn) error {
+	var leader string
+	var epoch int64
+	err := conn.QueryRow(ctx,
+		`SELECT a.application_name, COALESCE((SELECT epoch FROM leader_fence WHERE id = 1), 0)
+		 FROM pg_locks l JOIN pg_stat_activity a ON a.pid = l.pid
+		 WHERE l.locktype = 'advisory' AND l.granted AND l.classid::bigint = $1 AND l.objid::bigint = $2 AND l.objsubid = 1`,
+		PG_LEADER_LOCK_KEY>>32, PG_LEADER_LOCK_KEY&0xffffffff,
+	).Scan(&leader, &epoch)
+	if errors.Is(err, pgx.ErrNoRows) {
+		p.setLeader("", 0)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	p.setLeader(leader, epoch)
+	return nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrStaleLeader is returned for a leader-only write made with an epoch older than the
+// newest one registered through AdvanceLeaderEpoch, i.e. by a leader that has been replaced.
+var ErrStaleLeader = errors.New("a newer leader has taken over")
+
+// ErrFenceAhead is returned by AdvanceLeaderEpoch when the newest epoch was registered by a
+// different election backend, e.g. after switching from Postgres to etcd election. Epochs
+// of different backends are unrelated, so the new leader cannot catch up by itself; the
+// fence has to be reset while no server runs.
+var ErrFenceAhead = errors.New("leader fence is ahead of this election backend")
+
+// AdvanceLeaderEpoch registers epoch as the fencing token of a new leader, holder being its
+// identity and coordinator its election backend. From then on leader-only writes with an
+// older epoch fail with ErrStaleLeader. Registering an epoch that is not newer than the
+// current one fails the same way, unless holder reserved it for this term (see
+// coord.PostgresLeader), or with ErrFenceAhead if another backend registered the current
+// one. It waits for leader-only writes of the previous leader that are in progress.
+func (m *DBManager) AdvanceLeaderEpoch(epoch int64, holder, coordinator string) error {
+	ctx := context.Background()
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// The no-op upsert locks the fence row, creating it if need be
+	var current int64
+	var currentHolder, currentCoordinator string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO leader_fence (id, epoch) VALUES (1, 0) ON CONFLICT (id) DO UPDATE SET id = 1
+		 RETURNING epoch, holder, coordinator`,
+	).Scan(&current, &currentHolder, &currentCoordinator)
+	if err != nil {
+		return err
+	}
+	if err := fenceError(current, currentHolder, currentCoordinator, epoch, holder, coordinator); err != nil {
+		return err
+	}
+	if current < epoch {
+		if _, err := tx.Exec(ctx,
+			`UPDATE leader_fence SET epoch = $1, holder = $2, coordinator = $3, updated_at = now() WHERE id = 1`,
+			epoch, holder, coordinator); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// fenceError decides whether epoch may be registered over the current fence.
+func fenceError(current int64, currentHolder, currentCoordinator string, epoch int64, holder, coordinator string) error {
+	if current < epoch || (current == epoch && currentHolder == holder) {
+		return nil
+	}
+	if currentCoordinator != "" && currentCoordinator != coordinator {
+		return fmt.Errorf("%w: epoch %d from %s election is behind epoch %d registered by %s election",
+			ErrFenceAhead, epoch, coordinator, current, currentCoordinator)
+	}
+	return fmt.Errorf("%w: epoch %d is not newer than %d", ErrStaleLeader, epoch, current)
+}
+
+// checkEpoch fails with ErrStaleLeader if epoch is older than the newest leader epoch. The
+// fence row stays share-locked until tx ends, so a new leader cannot register in between.
+func checkEpoch(ctx context.Context, tx pgx.Tx, epoch int64) error {
+	var current int64
+	if err := tx.QueryRow(ctx, `SELECT epoch FROM leader_fence WHERE id = 1 FOR SHARE`).Scan(&current); err != nil {
+		return err
+	}
+	if current > epoch {
+		return fmt.Errorf("%w: epoch %d is older than %d", ErrStaleLeader, epoch, current)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"unicode/utf8"
+)
+
+// HISTORY_OUTPUT_LIMIT caps the output, stdout and stderr kept per attempt in bytes. The
+// task row keeps the full output of the latest attempt.
+const HISTORY_OUTPUT_LIMIT = 16 * 1024
+
+// Attempt is one run of a task. It is recorded in task_history when a worker claims the
+// task and finished when the run ends; FinishedAt is unset while it runs.
+type Attempt struct {
+	Attempt    int32
+	WorkerID   string
+	Host       string
+	Status     string
+	StartedAt  time.Time
+	FinishedAt sql.NullTime
+	Output     string
+	// Result is nil when the run ended without its worker reporting back, e.g. because the
+	// worker died.
+	Result *ExecutionResult
+}
+
+// truncateOutput shortens s to HISTORY_OUTPUT_LIMIT bytes without splitting a character.
+func truncateOutput(s string) string {
+	if len(s) <= HISTORY_OUTPUT_LIMIT {
+		return s
+	}
+	cut := HISTORY_OUTPUT_LIMIT
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "\n[truncated]"
+}
+
+// closeOpenAttempts finishes the running attempts of a task with status and output, for runs
+// that end without their worker reporting back.
+func closeOpenAttempts(ctx context.Context, db execer, id, status, output string, now time.Time) error {
+	_, err := db.Exec(ctx,
+		`UPDATE task_history SET status=$2, end_time=$3, result=$4 WHERE task_id=$1 AND attempt IS NOT NULL AND end_time IS NULL`,
+		id, status, now, nullableString(truncateOutput(output)),
+	)
+	return err
+}
+
+// GetJobHistory returns every attempt of a task, oldest first. Unknown tasks have none.
+func (m *DBManager) GetJobHistory(id string) ([]*Attempt, error) {
+	ctx := context.Background()
+	rows, err := m.pool.Query(ctx,
+		`SELECT attempt, COALESCE(worker_id, ''), COALESCE(host, ''), status, start_time, end_time, COALESCE(result, ''),
+		        COALESCE(stdout, ''), COALESCE(stderr, ''), exit_code, COALESCE(signal, ''), COALESCE(wall_time_ms, 0), COALESCE(user_cpu_ms, 0), COALESCE(system_cpu_ms, 0), COALESCE(max_rss_kb, 0)
+		 FROM task_history WHERE task_id=$1 AND attempt IS NOT NULL ORDER BY attempt`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []*Attempt
+	for rows.Next() {
+		a := &Attempt{}
+		res := &ExecutionResult{}
+		var exitCode sql.NullInt32
+		if err := rows.Scan(&a.Attempt, &a.WorkerID, &a.Host, &a.Status, &a.StartedAt, &a.FinishedAt, &a.Output,
+			&res.Stdout, &res.Stderr, &exitCode, &res.Signal, &res.WallTimeMs, &res.UserCPUMs, &res.SystemCPUMs, &res.MaxRSSKB); err != nil {
+			return nil, err
+		}
+		if exitCode.Valid {
+			res.ExitCode = exitCode.Int32
+			a.Result = res
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
@@ -0,0 +1,759 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryOutboxEntry is an outbox row of MemoryStore.
+type memoryOutboxEntry struct {
+	OutboxEntry
+	availableAt time.Time
+	lastError   string
+}
+
+// MemoryStore is a Store that keeps everything in process memory, for tests and the
+// standalone mode. It follows the Postgres semantics of DBManager, but nothing survives a
+// restart.
+type MemoryStore struct {
+	mu        sync.Mutex
+	jobs      map[string]*Job
+	workflows map[string]*Workflow
+	deps      map[string][]string // task ID -> IDs it depends on
+	history   map[string][]*Attempt
+	outbox    []*memoryOutboxEntry
+	outboxSeq int64
+	// leaderEpoch is the newest epoch passed to AdvanceLeaderEpoch. Every leader write
+	// checks it under mu, which also covers the per-task leader_epoch of DBManager.
+	leaderEpoch       int64
+	leaderHolder      string
+	leaderCoordinator string
+
+	// relayMu serializes RelayOutbox, which publishes without holding mu.
+	relayMu sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs:      make(map[string]*Job),
+		workflows: make(map[string]*Workflow),
+		deps:      make(map[string][]string),
+		history:   make(map[string][]*Attempt),
+	}
+}
+
+// copyJob returns a copy callers may keep after mu is released.
+func copyJob(job *Job) *Job {
+	c := *job
+	if job.Result != nil {
+		res := *job.Result
+		c.Result = &res
+	}
+	return &c
+}
+
+// insertLocked mirrors insertJob. Callers must hold mu.
+func (m *MemoryStore) insertLocked(job *Job, status string, now int64) (bool, error) {
+	if job.IdempotencyKey.Valid && m.jobByKey(job.IdempotencyKey.String) != nil {
+		return false, nil
+	}
+	if _, ok := m.jobs[job.ID]; ok {
+		return false, ErrJobExists
+	}
+	c := copyJob(job)
+	c.Status = status
+	c.Output = sql.NullString{}
+	c.Retries = 0
+	c.Result = nil
+	c.CreatedAt, c.UpdatedAt = now, now
+	m.jobs[c.ID] = c
+	return true, nil
+}
+
+func (m *MemoryStore) jobByKey(key string) *Job {
+	for _, job := range m.jobs {
+		if job.IdempotencyKey.Valid && job.IdempotencyKey.String == key {
+			return job
+		}
+	}
+	return nil
+}
+
+// enqueueLocked mirrors enqueueOutbox. Callers must hold mu.
+func (m *MemoryStore) enqueueLocked(ids ...string) {
+	now := time.Now()
+	for _, id := range ids {
+		job, ok := m.jobs[id]
+		if !ok {
+			continue
+		}
+		m.outboxSeq++
+		m.outbox = append(m.outbox, &memoryOutboxEntry{
+			OutboxEntry: OutboxEntry{ID: m.outboxSeq, TaskID: id, Queue: job.Queue, Priority: job.Priority},
+			availableAt: now,
+		})
+	}
+}
+
+func (m *MemoryStore) CreateJob(job *Job, keySince int64) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if job.IdempotencyKey.Valid {
+		if existing := m.jobByKey(job.IdempotencyKey.String); existing != nil {
+			if existing.CreatedAt >= keySince {
+				return copyJob(existing), nil
+			}
+			existing.IdempotencyKey = sql.NullString{}
+		}
+	}
+	if _, err := m.insertLocked(job, "PENDING", time.Now().Unix()); err != nil {
+		return nil, err
+	}
+	if !job.CronExpr.Valid && !job.ExecuteAt.Valid {
+		m.enqueueLocked(job.ID)
+	}
+	return nil, nil
+}
+
+func (m *MemoryStore) GetJob(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return copyJob(job), nil
+}
+
+func (m *MemoryStore) GetJobs(ids []string) (map[string]*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make(map[string]*Job, len(ids))
+	for _, id := range ids {
+		if job, ok := m.jobs[id]; ok {
+			jobs[id] = copyJob(job)
+		}
+	}
+	return jobs, nil
+}
+
+func (m *MemoryStore) ListJobs(f JobFilter) ([]*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := func(job *Job) int64 { return job.CreatedAt }
+	if f.OrderBy == "updated_at" {
+		key = func(job *Job) int64 { return job.UpdatedAt }
+	}
+	// less reports whether a sorts before b in the requested direction
+	less := func(a, b *Job) bool {
+		ka, kb := key(a), key(b)
+		if ka != kb {
+			return (ka < kb) == f.Ascending
+		}
+		return a.ID != b.ID && (a.ID < b.ID) == f.Ascending
+	}
+	cursor := &Job{ID: f.AfterID, CreatedAt: f.AfterValue, UpdatedAt: f.AfterValue}
+	statuses := make(map[string]bool, len(f.Statuses))
+	for _, s := range f.Statuses {
+		statuses[s] = true
+	}
+	contains := strings.ToLower(f.CommandContains)
+
+	var jobs []*Job
+	for _, job := range m.jobs {
+		switch {
+		case len(statuses) > 0 && !statuses[job.Status],
+			f.CreatedAfter > 0 && job.CreatedAt < f.CreatedAfter,
+			f.CreatedBefore > 0 && job.CreatedAt >= f.CreatedBefore,
+			f.UpdatedAfter > 0 && job.UpdatedAt < f.UpdatedAfter,
+			f.UpdatedBefore > 0 && job.UpdatedAt >= f.UpdatedBefore,
+			contains != "" && !strings.Contains(strings.ToLower(job.Command), contains),
+			f.Cron != nil && *f.Cron != job.CronExpr.Valid,
+			f.Queue != "" && job.Queue != f.Queue,
+			f.AfterID != "" && !less(cursor, job):
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return less(jobs[i], jobs[j]) })
+	if len(jobs) > f.Limit {
+		jobs = jobs[:f.Limit]
+	}
+	for i, job := range jobs {
+		jobs[i] = copyJob(job)
+	}
+	return jobs, nil
+}
+
+// update applies fn to the task with the given ID under mu and bumps updated_at if fn
+// reports a change. It returns sql.ErrNoRows for unknown IDs.
+func (m *MemoryStore) update(id string, fn func(job *Job) bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if fn(job) {
+		job.UpdatedAt = time.Now().Unix()
+	}
+	return nil
+}
+
+func (m *MemoryStore) ClaimJob(id, workerID, host string) (int32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok || job.Status != "PENDING" {
+		return 0, nil
+	}
+	now := time.Now()
+	job.Status = "RUNNING"
+	job.Output = sql.NullString{}
+	job.ExecuteAt = sql.NullTime{}
+	job.UpdatedAt = now.Unix()
+	m.closeAttemptsLocked(id, "FAILED", "[auto] attempt abandoned", now)
+	attempt := int32(len(m.history[id]) + 1)
+	m.history[id] = append(m.history[id], &Attempt{Attempt: attempt, WorkerID: workerID, Host: host, Status: "RUNNING", StartedAt: now})
+	return attempt, nil
+}
+
+// closeAttemptsLocked mirrors closeOpenAttempts. Callers must hold mu.
+func (m *MemoryStore) closeAttemptsLocked(id, status, output string, now time.Time) {
+	for _, a := range m.history[id] {
+		if !a.FinishedAt.Valid {
+			a.Status = status
+			a.FinishedAt = sql.NullTime{Time: now, Valid: true}
+			a.Output = truncateOutput(output)
+		}
+	}
+}
+
+func (m *MemoryStore) UpdateJobStatus(id, status string, output string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil // UPDATE of no rows
+	}
+	now := time.Now()
+	job.Status = status
+	job.Output = nullableString(output)
+	job.UpdatedAt = now.Unix()
+	if status == "SUCCEEDED" || status == "FAILED" || status == "CANCELLED" || status == "TIMED_OUT" {
+		m.closeAttemptsLocked(id, status, output, now)
+	}
+	return nil
+}
+
+func (m *MemoryStore) FinishAttempt(id string, attempt int32, status, output string, res *ExecutionResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	job.Status = status
+	job.Output = nullableString(output)
+	r := *res
+	job.Result = &r
+	job.UpdatedAt = now.Unix()
+	for _, a := range m.history[id] {
+		if a.Attempt == attempt {
+			a.Status = status
+			a.FinishedAt = sql.NullTime{Time: now, Valid: true}
+			a.Output = truncateOutput(output)
+			kept := r
+			kept.Stdout, kept.Stderr = truncateOutput(r.Stdout), truncateOutput(r.Stderr)
+			a.Result = &kept
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) CancelJob(id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return false, nil
+	}
+	switch {
+	case job.Status == "PENDING", job.Status == "BLOCKED":
+	case job.CronExpr.Valid && (job.Status == "SUCCEEDED" || job.Status == "FAILED" || job.Status == "TIMED_OUT"):
+	default:
+		return false, nil
+	}
+	job.Status = "CANCELLED"
+	job.UpdatedAt = time.Now().Unix()
+	kept := m.outbox[:0]
+	for _, e := range m.outbox {
+		if e.TaskID != id {
+			kept = append(kept, e)
+		}
+	}
+	m.outbox = kept
+	return true, nil
+}
+
+func (m *MemoryStore) ReplayJob(id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok || (job.Status != "FAILED" && job.Status != "TIMED_OUT") {
+		return false, nil
+	}
+	job.Status = "PENDING"
+	job.Retries = 0
+	job.UpdatedAt = time.Now().Unix()
+	for _, taskID := range m.downstreamLocked(id) {
+		if dep, ok := m.jobs[taskID]; ok && dep.Status == "SKIPPED" {
+			dep.Status = "BLOCKED"
+			dep.Output = sql.NullString{}
+			dep.UpdatedAt = job.UpdatedAt
+		}
+	}
+	m.enqueueLocked(id)
+	m.outbox[len(m.outbox)-1].Replay = true
+	return true, nil
+}
+
+func (m *MemoryStore) TouchJob(id string) error {
+	err := m.update(id, func(job *Job) bool {
+		return job.Status == "RUNNING"
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+func (m *MemoryStore) IncrementRetry(id string) (int32, int32, error) {
+	var retries, max int32
+	err := m.update(id, func(job *Job) bool {
+		job.Retries++
+		retries, max = job.Retries, job.MaxRetries
+		return true
+	})
+	return retries, max, err
+}
+
+func (m *MemoryStore) ResetToPending(id string, output string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	job.Status = "PENDING"
+	job.Output = nullableString(output)
+	job.UpdatedAt = now.Unix()
+	m.closeAttemptsLocked(id, "FAILED", output, now)
+	return nil
+}
+
+func (m *MemoryStore) GetJobHistory(id string) ([]*Attempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attempts := make([]*Attempt, 0, len(m.history[id]))
+	for _, a := range m.history[id] {
+		c := *a
+		if a.Result != nil {
+			res := *a.Result
+			c.Result = &res
+		}
+		attempts = append(attempts, &c)
+	}
+	return attempts, nil
+}
+
+func (m *MemoryStore) GetDueTaskIDs(limit int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var due []*Job
+	for _, job := range m.jobs {
+		if !job.CronExpr.Valid {
+			if job.Status == "PENDING" && job.ExecuteAt.Valid && !job.ExecuteAt.Time.After(now) {
+				due = append(due, job)
+			}
+			continue
+		}
+		switch job.Status {
+		case "PENDING", "SUCCEEDED", "FAILED", "TIMED_OUT":
+			if job.NextRunAt.Valid && !job.NextRunAt.Time.After(now) {
+				due = append(due, job)
+			}
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].UpdatedAt < due[j].UpdatedAt })
+	ids := make([]string, 0, min(limit, len(due)))
+	for _, job := range due {
+		if len(ids) == limit {
+			break
+		}
+		ids = append(ids, job.ID)
+	}
+	return ids, nil
+}
+
+func (m *MemoryStore) EnqueueDueTask(job *Job, nextRun time.Time, epoch int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkEpochLocked(epoch); err != nil {
+		return false, err
+	}
+	cur, ok := m.jobs[job.ID]
+	if !ok {
+		return false, nil
+	}
+	if job.CronExpr.Valid {
+		if cur.NextRunAt.Valid != job.NextRunAt.Valid || !cur.NextRunAt.Time.Equal(job.NextRunAt.Time) {
+			return false, nil
+		}
+		switch cur.Status {
+		case "PENDING", "SUCCEEDED", "FAILED", "TIMED_OUT":
+		default:
+			return false, nil
+		}
+		cur.NextRunAt = sql.NullTime{Time: nextRun, Valid: true}
+		cur.Status = "PENDING"
+		cur.Retries = 0
+	} else {
+		if cur.Status != "PENDING" || !cur.ExecuteAt.Valid {
+			return false, nil
+		}
+		cur.ExecuteAt = sql.NullTime{}
+	}
+	cur.UpdatedAt = time.Now().Unix()
+	m.enqueueLocked(job.ID)
+	return true, nil
+}
+
+func (m *MemoryStore) MarkStaleRunningJobsFailed(cutoffSeconds, epoch int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkEpochLocked(epoch); err != nil {
+		return 0, err
+	}
+	now := time.Now().Unix()
+	var n int64
+	for _, job := range m.jobs {
+		if job.Status != "RUNNING" || job.UpdatedAt >= now-max(cutoffSeconds, int64(job.TimeoutSeconds)+60) {
+			continue
+		}
+		job.Status = "FAILED"
+		job.Output = sql.NullString{String: job.Output.String + "\n[auto] marked failed due to staleness", Valid: true}
+		job.UpdatedAt = now
+		m.closeAttemptsLocked(job.ID, "FAILED", "[auto] marked failed due to staleness", time.Unix(now, 0))
+		n++
+	}
+	return n, nil
+}
+
+func (m *MemoryStore) ReclaimRunningJob(id, output string, epoch int64) (string, int32, int32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkEpochLocked(epoch); err != nil {
+		return "", 0, 0, err
+	}
+	job, ok := m.jobs[id]
+	if !ok || job.Status != "RUNNING" {
+		return "", 0, 0, nil
+	}
+	now := time.Now()
+	job.Retries++
+	job.Status = "PENDING"
+	if job.Retries > job.MaxRetries {
+		job.Status = "FAILED"
+	}
+	job.Output = nullableString(output)
+	job.UpdatedAt = now.Unix()
+	m.closeAttemptsLocked(id, "FAILED", output, now)
+	if job.Status == "FAILED" {
+		m.skipDependentsLocked(id)
+	}
+	return job.Status, job.Retries, job.MaxRetries, nil
+}
+
+func (m *MemoryStore) RelayOutbox(limit int, publish func(e *OutboxEntry) error) (int, error) {
+	m.relayMu.Lock()
+	defer m.relayMu.Unlock()
+
+	m.mu.Lock()
+	now := time.Now()
+	var entries []OutboxEntry
+	for _, e := range m.outbox {
+		if len(entries) == limit {
+			break
+		}
+		if !e.availableAt.After(now) {
+			entries = append(entries, e.OutboxEntry)
+		}
+	}
+	m.mu.Unlock()
+
+	failed := make(map[int64]error)
+	for i := range entries {
+		if err := publish(&entries[i]); err != nil {
+			failed[entries[i].ID] = err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	done := make(map[int64]bool, len(entries))
+	for _, e := range entries {
+		if failed[e.ID] == nil {
+			done[e.ID] = true
+		}
+	}
+	kept := m.outbox[:0]
+	for _, e := range m.outbox {
+		if done[e.ID] {
+			continue
+		}
+		if err := failed[e.ID]; err != nil {
+			e.Attempts++
+			e.lastError = err.Error()
+			e.availableAt = time.Now().Add(OUTBOX_RETRY_DELAY)
+		}
+		kept = append(kept, e)
+	}
+	m.outbox = kept
+	return len(done), nil
+}
+
+func (m *MemoryStore) CreateWorkflow(wf *Workflow, jobs []*Job, deps map[string][]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.workflows[wf.ID]; ok {
+		return fmt.Errorf("workflow %s already exists", wf.ID)
+	}
+	for _, job := range jobs {
+		if _, ok := m.jobs[job.ID]; ok {
+			return ErrJobExists
+		}
+	}
+	c := *wf
+	m.workflows[wf.ID] = &c
+	now := time.Now().Unix()
+	var roots []string
+	for _, job := range jobs {
+		status := "PENDING"
+		if len(deps[job.ID]) > 0 {
+			status = "BLOCKED"
+		} else {
+			roots = append(roots, job.ID)
+		}
+		if _, err := m.insertLocked(job, status, now); err != nil {
+			return err
+		}
+	}
+	for id, parents := range deps {
+		m.deps[id] = append(m.deps[id], parents...)
+	}
+	m.enqueueLocked(roots...)
+	return nil
+}
+
+func (m *MemoryStore) GetWorkflow(id string) (*Workflow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wf, ok := m.workflows[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	c := *wf
+	return &c, nil
+}
+
+func (m *MemoryStore) WorkflowJobs(id string) ([]*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var jobs []*Job
+	for _, job := range m.jobs {
+		if job.WorkflowID.Valid && job.WorkflowID.String == id {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].CreatedAt != jobs[j].CreatedAt {
+			return jobs[i].CreatedAt < jobs[j].CreatedAt
+		}
+		return jobs[i].WorkflowNode.String < jobs[j].WorkflowNode.String
+	})
+	return jobs, nil
+}
+
+func (m *MemoryStore) WorkflowDependencies(id string) (map[string][]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	deps := make(map[string][]string)
+	for taskID, parents := range m.deps {
+		job, ok := m.jobs[taskID]
+		if !ok || !job.WorkflowID.Valid || job.WorkflowID.String != id {
+			continue
+		}
+		sorted := append([]string(nil), parents...)
+		sort.Strings(sorted)
+		deps[taskID] = sorted
+	}
+	return deps, nil
+}
+
+func (m *MemoryStore) ReleaseBlockedTasks(parentID string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.releaseLocked(parentID), nil
+}
+
+func (m *MemoryStore) ReleaseReadyTasks(epoch int64) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkEpochLocked(epoch); err != nil {
+		return nil, err
+	}
+	return m.releaseLocked(""), nil
+}
+
+// releaseLocked mirrors ReleaseBlockedTasks. Callers must hold mu.
+func (m *MemoryStore) releaseLocked(parentID string) []string {
+	now := time.Now().Unix()
+	var ids []string
+	for taskID, parents := range m.deps {
+		job, ok := m.jobs[taskID]
+		if !ok || job.Status != "BLOCKED" {
+			continue
+		}
+		ready, direct := true, parentID == ""
+		for _, p := range parents {
+			if p == parentID {
+				direct = true
+			}
+			if parent, ok := m.jobs[p]; !ok || parent.Status != "SUCCEEDED" {
+				ready = false
+			}
+		}
+		if !ready || !direct {
+			continue
+		}
+		job.Status = "PENDING"
+		job.UpdatedAt = now
+		ids = append(ids, taskID)
+	}
+	m.enqueueLocked(ids...)
+	return ids
+}
+
+func (m *MemoryStore) SkipDependents(id string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.skipDependentsLocked(id), nil
+}
+
+// skipDependentsLocked mirrors SkipDependents. Callers must hold mu.
+func (m *MemoryStore) skipDependentsLocked(id string) int64 {
+	now := time.Now().Unix()
+	var n int64
+	for _, taskID := range m.downstreamLocked(id) {
+		if job, ok := m.jobs[taskID]; ok && job.Status == "BLOCKED" {
+			job.Status = "SKIPPED"
+			job.Output = nullableString("Skipped: upstream job " + id + " did not succeed")
+			job.UpdatedAt = now
+			n++
+		}
+	}
+	return n
+}
+
+// downstreamLocked returns the tasks that depend on id directly or transitively, walking
+// breadth-first. Callers must hold mu.
+func (m *MemoryStore) downstreamLocked(id string) []string {
+	seen := map[string]bool{}
+	frontier := []string{id}
+	var ids []string
+	for len(frontier) > 0 {
+		var next []string
+		for taskID, parents := range m.deps {
+			if seen[taskID] {
+				continue
+			}
+			for _, p := range parents {
+				if slices.Contains(frontier, p) {
+					seen[taskID] = true
+					next = append(next, taskID)
+					break
+				}
+			}
+		}
+		ids = append(ids, next...)
+		frontier = next
+	}
+	return ids
+}
+
+func (m *MemoryStore) SkipStrandedTasks(graceSeconds, epoch int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkEpochLocked(epoch); err != nil {
+		return 0, err
+	}
+	now := time.Now().Unix()
+	var n int64
+	for taskID, parents := range m.deps {
+		job, ok := m.jobs[taskID]
+		if !ok || job.Status != "BLOCKED" {
+			continue
+		}
+		for _, p := range parents {
+			parent, ok := m.jobs[p]
+			if !ok {
+				continue
+			}
+			stranded := parent.Status == "CANCELLED" || parent.Status == "SKIPPED" ||
+				((parent.Status == "FAILED" || parent.Status == "TIMED_OUT") && parent.UpdatedAt < now-graceSeconds)
+			if stranded {
+				job.Status = "SKIPPED"
+				job.Output = nullableString("Skipped: upstream job " + parent.ID + " finished with status " + parent.Status)
+				job.UpdatedAt = now
+				n++
+				break
+			}
+		}
+	}
+	return n, nil
+}
+
+func (m *MemoryStore) AdvanceLeaderEpoch(epoch int64, holder, coordinator string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := fenceError(m.leaderEpoch, m.leaderHolder, m.leaderCoordinator, epoch, holder, coordinator); err != nil {
+		return err
+	}
+	m.leaderEpoch, m.leaderHolder, m.leaderCoordinator = epoch, holder, coordinator
+	return nil
+}
+
+// checkEpochLocked mirrors checkEpoch. Callers must hold mu.
+func (m *MemoryStore) checkEpochLocked(epoch int64) error {
+	if m.leaderEpoch > epoch {
+		return fmt.Errorf("%w: epoch %d is older than %d", ErrStaleLeader, epoch, m.leaderEpoch)
+	}
+	return nil
+}
+
+// Close does nothing; the data lives as long as the MemoryStore.
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,522 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newJob(id string) *Job {
+	return &Job{ID: id, Command: "true", Queue: "default", MaxRetries: 1}
+}
+
+func mustCreate(t *testing.T, s *MemoryStore, job *Job) {
+	t.Helper()
+	if _, err := s.CreateJob(job, 0); err != nil {
+		t.Fatalf("CreateJob(%s): %v", job.ID, err)
+	}
+}
+
+func mustStatus(t *testing.T, s *MemoryStore, id, want string) *Job {
+	t.Helper()
+	job, err := s.GetJob(id)
+	if err != nil {
+		t.Fatalf("GetJob(%s): %v", id, err)
+	}
+	if job.Status != want {
+		t.Fatalf("job %s has status %s, want %s", id, job.Status, want)
+	}
+	return job
+}
+
+// relayAll drains the outbox and returns the published entries.
+func relayAll(t *testing.T, s *MemoryStore) []OutboxEntry {
+	t.Helper()
+	var published []OutboxEntry
+	if _, err := s.RelayOutbox(100, func(e *OutboxEntry) error {
+		published = append(published, *e)
+		return nil
+	}); err != nil {
+		t.Fatalf("RelayOutbox: %v", err)
+	}
+	return published
+}
+
+func TestCreateJobQueuesThroughOutbox(t *testing.T) {
+	s := NewMemoryStore()
+	mustCreate(t, s, newJob("a"))
+	delayed := newJob("b")
+	delayed.ExecuteAt = sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}
+	mustCreate(t, s, delayed)
+
+	published := relayAll(t, s)
+	if len(published) != 1 || published[0].TaskID != "a" || published[0].Replay {
+		t.Fatalf("published %+v, want a single entry for a", published)
+	}
+	if again := relayAll(t, s); len(again) != 0 {
+		t.Fatalf("published entries are relayed again: %+v", again)
+	}
+}
+
+func TestRelayOutboxRetriesFailedPublish(t *testing.T) {
+	s := NewMemoryStore()
+	mustCreate(t, s, newJob("a"))
+
+	n, err := s.RelayOutbox(100, func(e *OutboxEntry) error { return errors.New("queue down") })
+	if err != nil || n != 0 {
+		t.Fatalf("RelayOutbox = %d, %v; want 0, nil", n, err)
+	}
+	// The entry waits OUTBOX_RETRY_DELAY before it is due again
+	if published := relayAll(t, s); len(published) != 0 {
+		t.Fatalf("failed entry relayed before its retry delay: %+v", published)
+	}
+	s.mu.Lock()
+	if len(s.outbox) != 1 || s.outbox[0].Attempts != 1 {
+		t.Fatalf("outbox = %+v, want one entry with one attempt", s.outbox)
+	}
+	s.outbox[0].availableAt = time.Now()
+	s.mu.Unlock()
+	if published := relayAll(t, s); len(published) != 1 || published[0].Attempts != 1 {
+		t.Fatalf("published %+v, want the retried entry", published)
+	}
+}
+
+func TestCreateJobIdempotencyKey(t *testing.T) {
+	s := NewMemoryStore()
+	first := newJob("a")
+	first.IdempotencyKey = sql.NullString{String: "key", Valid: true}
+	mustCreate(t, s, first)
+
+	retry := newJob("b")
+	retry.IdempotencyKey = first.IdempotencyKey
+	existing, err := s.CreateJob(retry, 0)
+	if err != nil || existing == nil || existing.ID != "a" {
+		t.Fatalf("CreateJob with a used key = %v, %v; want job a", existing, err)
+	}
+	if _, err := s.GetJob("b"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("duplicate submission created job b: %v", err)
+	}
+
+	// Outside the window the key is given to the new job
+	existing, err = s.CreateJob(retry, time.Now().Unix()+1)
+	if err != nil || existing != nil {
+		t.Fatalf("CreateJob after the window = %v, %v; want a new job", existing, err)
+	}
+	mustStatus(t, s, "b", "PENDING")
+}
+
+func TestClaimJobOnlyFromPending(t *testing.T) {
+	s := NewMemoryStore()
+	mustCreate(t, s, newJob("a"))
+
+	attempt, err := s.ClaimJob("a", "w1", "host")
+	if err != nil || attempt != 1 {
+		t.Fatalf("ClaimJob = %d, %v; want attempt 1", attempt, err)
+	}
+	mustStatus(t, s, "a", "RUNNING")
+	if attempt, _ := s.ClaimJob("a", "w2", "host"); attempt != 0 {
+		t.Fatalf("running job claimed again as attempt %d", attempt)
+	}
+
+	if err := s.FinishAttempt("a", 1, "SUCCEEDED", "ok", &ExecutionResult{}); err != nil {
+		t.Fatalf("FinishAttempt: %v", err)
+	}
+	history, err := s.GetJobHistory("a")
+	if err != nil || len(history) != 1 || history[0].Status != "SUCCEEDED" || history[0].WorkerID != "w1" {
+		t.Fatalf("history = %+v, %v; want one SUCCEEDED attempt by w1", history, err)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	s := NewMemoryStore()
+	mustCreate(t, s, newJob("a"))
+	if ok, err := s.CancelJob("a"); err != nil || !ok {
+		t.Fatalf("CancelJob(PENDING) = %v, %v; want true", ok, err)
+	}
+	mustStatus(t, s, "a", "CANCELLED")
+	if published := relayAll(t, s); len(published) != 0 {
+		t.Fatalf("cancelled job still relayed: %+v", published)
+	}
+	if ok, _ := s.CancelJob("a"); ok {
+		t.Fatal("CancelJob succeeded on a finished job")
+	}
+	if attempt, _ := s.ClaimJob("a", "w1", "host"); attempt != 0 {
+		t.Fatal("cancelled job was claimed")
+	}
+}
+
+func TestReplayJobWritesReplayOutboxEntry(t *testing.T) {
+	s := NewMemoryStore()
+	mustCreate(t, s, newJob("a"))
+	relayAll(t, s)
+	s.ClaimJob("a", "w1", "host")
+	s.IncrementRetry("a")
+	if err := s.FinishAttempt("a", 1, "FAILED", "boom", &ExecutionResult{ExitCode: 1}); err != nil {
+		t.Fatalf("FinishAttempt: %v", err)
+	}
+
+	if ok, err := s.ReplayJob("a"); err != nil || !ok {
+		t.Fatalf("ReplayJob = %v, %v; want true", ok, err)
+	}
+	job := mustStatus(t, s, "a", "PENDING")
+	if job.Retries != 0 {
+		t.Fatalf("replayed job has %d retries, want 0", job.Retries)
+	}
+	published := relayAll(t, s)
+	if len(published) != 1 || published[0].TaskID != "a" || !published[0].Replay {
+		t.Fatalf("published %+v, want one replay entry for a", published)
+	}
+	if ok, _ := s.ReplayJob("a"); ok {
+		t.Fatal("ReplayJob succeeded on a PENDING job")
+	}
+}
+
+func TestMarkStaleRunningJobsFailedHonoursTouch(t *testing.T) {
+	s := NewMemoryStore()
+	mustCreate(t, s, newJob("alive"))
+	mustCreate(t, s, newJob("dead"))
+	s.ClaimJob("alive", "w1", "host")
+	s.ClaimJob("dead", "w2", "host")
+	s.mu.Lock()
+	for _, job := range s.jobs {
+		job.UpdatedAt -= 3600
+	}
+	s.mu.Unlock()
+
+	// Only the worker of "alive" still heartbeats
+	if err := s.TouchJob("alive"); err != nil {
+		t.Fatalf("TouchJob: %v", err)
+	}
+	n, err := s.MarkStaleRunningJobsFailed(600, 0)
+	if err != nil || n != 1 {
+		t.Fatalf("MarkStaleRunningJobsFailed = %d, %v; want 1", n, err)
+	}
+	mustStatus(t, s, "alive", "RUNNING")
+	mustStatus(t, s, "dead", "FAILED")
+	history, _ := s.GetJobHistory("dead")
+	if len(history) != 1 || history[0].Status != "FAILED" || !history[0].FinishedAt.Valid {
+		t.Fatalf("attempt of stale job not closed: %+v", history)
+	}
+}
+
+func TestTouchJobIgnoresFinishedJobs(t *testing.T) {
+	s := NewMemoryStore()
+	mustCreate(t, s, newJob("a"))
+	s.UpdateJobStatus("a", "SUCCEEDED", "")
+	before := mustStatus(t, s, "a", "SUCCEEDED").UpdatedAt
+	s.mu.Lock()
+	s.jobs["a"].UpdatedAt = before - 100
+	s.mu.Unlock()
+	if err := s.TouchJob("a"); err != nil {
+		t.Fatalf("TouchJob: %v", err)
+	}
+	if got := mustStatus(t, s, "a", "SUCCEEDED").UpdatedAt; got != before-100 {
+		t.Fatalf("TouchJob changed a finished job")
+	}
+	if err := s.TouchJob("missing"); err != nil {
+		t.Fatalf("TouchJob on an unknown job: %v", err)
+	}
+}
+
+func TestReclaimRunningJob(t *testing.T) {
+	s := NewMemoryStore()
+	job := newJob("a")
+	job.MaxRetries = 1
+	wf := &Workflow{ID: "wf"}
+	child := newJob("b")
+	if err := s.CreateWorkflow(wf, []*Job{job, child}, map[string][]string{"b": {"a"}}); err != nil {
+		t.Fatalf("CreateWorkflow: %v", err)
+	}
+
+	if status, _, _, err := s.ReclaimRunningJob("a", "lost", 0); err != nil || status != "" {
+		t.Fatalf("reclaim of a PENDING job = %q, %v; want no change", status, err)
+	}
+
+	s.ClaimJob("a", "w1", "host")
+	status, retries, max, err := s.ReclaimRunningJob("a", "lost", 0)
+	if err != nil || status != "PENDING" || retries != 1 || max != 1 {
+		t.Fatalf("first reclaim = %q %d/%d, %v; want PENDING 1/1", status, retries, max, err)
+	}
+	mustStatus(t, s, "b", "BLOCKED")
+
+	s.ClaimJob("a", "w2", "host")
+	status, retries, _, err = s.ReclaimRunningJob("a", "lost", 0)
+	if err != nil || status != "FAILED" || retries != 2 {
+		t.Fatalf("second reclaim = %q %d, %v; want FAILED 2", status, retries, err)
+	}
+	mustStatus(t, s, "a", "FAILED")
+	mustStatus(t, s, "b", "SKIPPED")
+	history, _ := s.GetJobHistory("a")
+	for _, a := range history {
+		if a.Status != "FAILED" || !a.FinishedAt.Valid {
+			t.Fatalf("attempt %d left open: %+v", a.Attempt, a)
+		}
+	}
+}
+
+func TestEnqueueDueTask(t *testing.T) {
+	s := NewMemoryStore()
+	due := sql.NullTime{Time: time.Now().Add(-time.Second), Valid: true}
+	next := time.Now().Add(time.Minute)
+
+	once := newJob("once")
+	once.ExecuteAt = due
+	mustCreate(t, s, once)
+	if ok, err := s.EnqueueDueTask(once, time.Time{}, 0); err != nil || !ok {
+		t.Fatalf("EnqueueDueTask(one-time) = %v, %v; want true", ok, err)
+	}
+	if ok, _ := s.EnqueueDueTask(once, time.Time{}, 0); ok {
+		t.Fatal("one-time task enqueued twice")
+	}
+
+	cron := newJob("cron")
+	cron.CronExpr = sql.NullString{String: "* * * * *", Valid: true}
+	cron.NextRunAt = due
+	mustCreate(t, s, cron)
+	s.ClaimJob("cron", "w1", "host")
+	// The previous run is still in flight
+	if ok, err := s.EnqueueDueTask(cron, next, 0); err != nil || ok {
+		t.Fatalf("EnqueueDueTask(running cron) = %v, %v; want false", ok, err)
+	}
+	mustStatus(t, s, "cron", "RUNNING")
+
+	s.FinishAttempt("cron", 1, "FAILED", "", &ExecutionResult{ExitCode: 1})
+	if ok, err := s.EnqueueDueTask(cron, next, 0); err != nil || !ok {
+		t.Fatalf("EnqueueDueTask(finished cron) = %v, %v; want true", ok, err)
+	}
+	job := mustStatus(t, s, "cron", "PENDING")
+	if !job.NextRunAt.Time.Equal(next) || job.Retries != 0 {
+		t.Fatalf("cron run not advanced: next %v retries %d", job.NextRunAt.Time, job.Retries)
+	}
+	// A scan that read the old next_run_at loses the race
+	if ok, _ := s.EnqueueDueTask(cron, next.Add(time.Minute), 0); ok {
+		t.Fatal("cron run enqueued twice")
+	}
+
+	var ids []string
+	for _, e := range relayAll(t, s) {
+		ids = append(ids, e.TaskID)
+	}
+	if len(ids) != 2 || ids[0] != "once" || ids[1] != "cron" {
+		t.Fatalf("relayed %v, want [once cron]", ids)
+	}
+}
+
+func TestLeaderFencing(t *testing.T) {
+	s := NewMemoryStore()
+	mustCreate(t, s, newJob("a"))
+
+	if err := s.AdvanceLeaderEpoch(5, "node-1", "etcd"); err != nil {
+		t.Fatalf("AdvanceLeaderEpoch(5): %v", err)
+	}
+	// The same term may register its epoch again, e.g. after a retry
+	if err := s.AdvanceLeaderEpoch(5, "node-1", "etcd"); err != nil {
+		t.Fatalf("AdvanceLeaderEpoch(5) again: %v", err)
+	}
+	for _, tc := range []struct {
+		epoch  int64
+		holder string
+	}{{5, "node-2"}, {4, "node-1"}} {
+		if err := s.AdvanceLeaderEpoch(tc.epoch, tc.holder, "etcd"); !errors.Is(err, ErrStaleLeader) {
+			t.Fatalf("AdvanceLeaderEpoch(%d, %s) = %v, want ErrStaleLeader", tc.epoch, tc.holder, err)
+		}
+	}
+
+	stale := map[string]func() error{
+		"EnqueueDueTask": func() error { _, err := s.EnqueueDueTask(newJob("a"), time.Time{}, 4); return err },
+		"MarkStaleRunningJobsFailed": func() error {
+			_, err := s.MarkStaleRunningJobsFailed(600, 4)
+			return err
+		},
+		"ReclaimRunningJob": func() error { _, _, _, err := s.ReclaimRunningJob("a", "", 4); return err },
+		"ReleaseReadyTasks": func() error { _, err := s.ReleaseReadyTasks(4); return err },
+		"SkipStrandedTasks": func() error { _, err := s.SkipStrandedTasks(60, 4); return err },
+	}
+	for name, write := range stale {
+		if err := write(); !errors.Is(err, ErrStaleLeader) {
+			t.Errorf("%s with an old epoch = %v, want ErrStaleLeader", name, err)
+		}
+	}
+	if _, err := s.MarkStaleRunningJobsFailed(600, 5); err != nil {
+		t.Fatalf("MarkStaleRunningJobsFailed with the current epoch: %v", err)
+	}
+	if err := s.AdvanceLeaderEpoch(6, "node-2", "etcd"); err != nil {
+		t.Fatalf("AdvanceLeaderEpoch(6): %v", err)
+	}
+	if _, err := s.MarkStaleRunningJobsFailed(600, 5); !errors.Is(err, ErrStaleLeader) {
+		t.Fatalf("replaced leader wrote: %v", err)
+	}
+}
+
+func TestLeaderFenceOfAnotherBackend(t *testing.T) {
+	s := NewMemoryStore()
+	if err := s.AdvanceLeaderEpoch(10, "node-1", "postgres"); err != nil {
+		t.Fatalf("AdvanceLeaderEpoch(10): %v", err)
+	}
+	if err := s.AdvanceLeaderEpoch(5, "node-2", "etcd"); !errors.Is(err, ErrFenceAhead) {
+		t.Fatalf("etcd epoch behind a postgres fence = %v, want ErrFenceAhead", err)
+	}
+	if err := s.AdvanceLeaderEpoch(5, "node-2", "postgres"); !errors.Is(err, ErrStaleLeader) {
+		t.Fatalf("older postgres epoch = %v, want ErrStaleLeader", err)
+	}
+	// A newer epoch of another backend takes over the fence
+	if err := s.AdvanceLeaderEpoch(11, "node-2", "etcd"); err != nil {
+		t.Fatalf("AdvanceLeaderEpoch(11) from etcd: %v", err)
+	}
+	if err := s.AdvanceLeaderEpoch(11, "node-1", "postgres"); !errors.Is(err, ErrFenceAhead) {
+		t.Fatalf("postgres epoch equal to an etcd fence = %v, want ErrFenceAhead", err)
+	}
+
+	// A fence written before the backend was recorded reports a stale leader
+	s = NewMemoryStore()
+	s.leaderEpoch = 10
+	if err := s.AdvanceLeaderEpoch(5, "node-2", "etcd"); !errors.Is(err, ErrStaleLeader) {
+		t.Fatalf("fence without a backend = %v, want ErrStaleLeader", err)
+	}
+}
+
+func TestWorkflowReleaseAndSkip(t *testing.T) {
+	s := NewMemoryStore()
+	// a -> b -> d, a -> c
+	jobs := []*Job{newJob("a"), newJob("b"), newJob("c"), newJob("d")}
+	deps := map[string][]string{"b": {"a"}, "c": {"a"}, "d": {"b"}}
+	if err := s.CreateWorkflow(&Workflow{ID: "wf"}, jobs, deps); err != nil {
+		t.Fatalf("CreateWorkflow: %v", err)
+	}
+	mustStatus(t, s, "a", "PENDING")
+	for _, id := range []string{"b", "c", "d"} {
+		mustStatus(t, s, id, "BLOCKED")
+	}
+	if published := relayAll(t, s); len(published) != 1 || published[0].TaskID != "a" {
+		t.Fatalf("published %+v, want only the root", published)
+	}
+
+	if ids, _ := s.ReleaseBlockedTasks("a"); len(ids) != 0 {
+		t.Fatalf("released %v before a succeeded", ids)
+	}
+	s.UpdateJobStatus("a", "SUCCEEDED", "")
+	ids, err := s.ReleaseBlockedTasks("a")
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("ReleaseBlockedTasks(a) = %v, %v; want b and c", ids, err)
+	}
+	mustStatus(t, s, "d", "BLOCKED")
+	if again, _ := s.ReleaseBlockedTasks("a"); len(again) != 0 {
+		t.Fatalf("released %v twice", again)
+	}
+
+	s.UpdateJobStatus("b", "FAILED", "")
+	if n, err := s.SkipDependents("b"); err != nil || n != 1 {
+		t.Fatalf("SkipDependents(b) = %d, %v; want 1", n, err)
+	}
+	d := mustStatus(t, s, "d", "SKIPPED")
+	if d.Output.String != "Skipped: upstream job b did not succeed" {
+		t.Fatalf("skipped job output = %q", d.Output.String)
+	}
+}
+
+func TestReplayJobUnskipsDependents(t *testing.T) {
+	s := NewMemoryStore()
+	// a -> b -> c, d independent
+	jobs := []*Job{newJob("a"), newJob("b"), newJob("c"), newJob("d")}
+	deps := map[string][]string{"b": {"a"}, "c": {"b"}}
+	if err := s.CreateWorkflow(&Workflow{ID: "wf"}, jobs, deps); err != nil {
+		t.Fatalf("CreateWorkflow: %v", err)
+	}
+	relayAll(t, s)
+	s.UpdateJobStatus("a", "FAILED", "")
+	if n, err := s.SkipDependents("a"); err != nil || n != 2 {
+		t.Fatalf("SkipDependents(a) = %d, %v; want 2", n, err)
+	}
+	s.UpdateJobStatus("d", "SUCCEEDED", "")
+
+	if ok, err := s.ReplayJob("a"); err != nil || !ok {
+		t.Fatalf("ReplayJob = %v, %v; want true", ok, err)
+	}
+	mustStatus(t, s, "a", "PENDING")
+	for _, id := range []string{"b", "c"} {
+		if job := mustStatus(t, s, id, "BLOCKED"); job.Output.Valid {
+			t.Fatalf("unskipped job %s kept output %q", id, job.Output.String)
+		}
+	}
+	mustStatus(t, s, "d", "SUCCEEDED")
+	if published := relayAll(t, s); len(published) != 1 || published[0].TaskID != "a" {
+		t.Fatalf("published %+v, want only the replayed job", published)
+	}
+
+	s.UpdateJobStatus("a", "SUCCEEDED", "")
+	if ids, err := s.ReleaseBlockedTasks("a"); err != nil || len(ids) != 1 || ids[0] != "b" {
+		t.Fatalf("ReleaseBlockedTasks(a) = %v, %v; want [b]", ids, err)
+	}
+}
+
+func TestSkipStrandedTasksWaitsForGrace(t *testing.T) {
+	s := NewMemoryStore()
+	deps := map[string][]string{"b": {"a"}}
+	if err := s.CreateWorkflow(&Workflow{ID: "wf"}, []*Job{newJob("a"), newJob("b")}, deps); err != nil {
+		t.Fatalf("CreateWorkflow: %v", err)
+	}
+	s.UpdateJobStatus("a", "FAILED", "")
+
+	// A failure within the grace period may still be retried
+	if n, err := s.SkipStrandedTasks(60, 0); err != nil || n != 0 {
+		t.Fatalf("SkipStrandedTasks within grace = %d, %v; want 0", n, err)
+	}
+	s.mu.Lock()
+	s.jobs["a"].UpdatedAt -= 120
+	s.mu.Unlock()
+	if n, err := s.SkipStrandedTasks(60, 0); err != nil || n != 1 {
+		t.Fatalf("SkipStrandedTasks after grace = %d, %v; want 1", n, err)
+	}
+	mustStatus(t, s, "b", "SKIPPED")
+}
+
+func TestReleaseReadyTasksSweepsEveryWorkflow(t *testing.T) {
+	s := NewMemoryStore()
+	for _, wf := range []string{"wf1", "wf2"} {
+		jobs := []*Job{newJob(wf + "-a"), newJob(wf + "-b")}
+		if err := s.CreateWorkflow(&Workflow{ID: wf}, jobs, map[string][]string{wf + "-b": {wf + "-a"}}); err != nil {
+			t.Fatalf("CreateWorkflow(%s): %v", wf, err)
+		}
+		s.UpdateJobStatus(wf+"-a", "SUCCEEDED", "")
+	}
+	relayAll(t, s)
+
+	ids, err := s.ReleaseReadyTasks(0)
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("ReleaseReadyTasks = %v, %v; want both children", ids, err)
+	}
+	if published := relayAll(t, s); len(published) != 2 {
+		t.Fatalf("published %+v, want both children", published)
+	}
+}
+
+func TestListJobsKeysetSkipsTheCursorRow(t *testing.T) {
+	s := NewMemoryStore()
+	for _, id := range []string{"a", "b", "c"} {
+		mustCreate(t, s, newJob(id)) // same created_at second, so id breaks ties
+	}
+	for _, ascending := range []bool{true, false} {
+		var ids []string
+		f := JobFilter{OrderBy: "created_at", Ascending: ascending, Limit: 1}
+		for len(ids) < 4 {
+			jobs, err := s.ListJobs(f)
+			if err != nil {
+				t.Fatalf("ListJobs: %v", err)
+			}
+			if len(jobs) == 0 {
+				break
+			}
+			ids = append(ids, jobs[0].ID)
+			f.AfterValue, f.AfterID = jobs[0].CreatedAt, jobs[0].ID
+		}
+		want := "a b c"
+		if !ascending {
+			want = "c b a"
+		}
+		if got := strings.Join(ids, " "); got != want {
+			t.Fatalf("ascending=%v paged %q, want %q", ascending, got, want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MIGRATION_LOCK_KEY is the advisory lock held while migrations run, so servers starting at
+// the same time apply each migration once.
+const MIGRATION_LOCK_KEY int64 = 0x7363686564756c65 // "schedule"
+
+// migrationFiles holds the versioned schema changes, named <version>_<name>.{up,down}.sql.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// ErrSchemaAhead is returned when the database has migrations this binary does not know,
+// i.e. it was migrated by a newer version.
+var ErrSchemaAhead = errors.New("database schema is newer than this binary")
+
+// ErrSchemaBehind is returned when the database lacks migrations this binary needs and the
+// process was not asked to apply them.
+var ErrSchemaBehind = errors.New("database schema is older than this binary")
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState is a migration together with whether and when it was applied.
+type MigrationState struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// loadMigrations reads the embedded migrations in version order.
+func loadMigrations() ([]Migration, error) {
+	return parseMigrations(migrationFiles)
+}
+
+// parseMigrations reads the migrations directory of fsys in version order.
+func parseMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		m := migrationName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", e.Name())
+		}
+		version, err := strconv.Atoi(m[1])
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("migration file %s: version must be a positive integer", e.Name())
+		}
+		body, err := fs.ReadFile(fsys, "migrations/"+e.Name())
+		if err != nil {
+			return nil, err
+		}
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, mig.Name, m[2])
+		}
+		part := &mig.Down
+		if m[3] == "up" {
+			part = &mig.Up
+		}
+		if *part != "" {
+			return nil, fmt.Errorf("migration %d has more than one %s file", version, m[3])
+		}
+		*part = string(body)
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies and reverts the embedded migrations, recording them in schema_migrations.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// NewMigrator connects to the database at dsn without changing its schema.
+func NewMigrator(dsn string) (*Migrator, error) {
+	pool, err := newPool(context.Background(), dsn)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMigrator(pool)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func newMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// Latest returns the newest version this binary knows.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// locked runs fn on a connection holding MIGRATION_LOCK_KEY, after making sure the
+// schema_migrations table exists. fn gets the applied versions and their times.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgx.Conn, applied map[int]time.Time) error) error {
+	c, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Release()
+	conn := c.Conn()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, MIGRATION_LOCK_KEY); err != nil {
+		return err
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, MIGRATION_LOCK_KEY)
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`); err != nil {
+		return err
+	}
+	applied, err := scanApplied(conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`))
+	if err != nil {
+		return err
+	}
+	return fn(conn, applied)
+}
+
+// scanApplied collects the versions and times of a schema_migrations query.
+func scanApplied(rows pgx.Rows, err error) (map[int]time.Time, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// Check returns ErrSchemaAhead or ErrSchemaBehind unless every migration this binary knows
+// has been applied. Unlike Up it takes no lock and changes nothing.
+func (m *Migrator) Check(ctx context.Context) error {
+	var exists bool
+	if err := m.pool.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return err
+	}
+	applied := make(map[int]time.Time)
+	if exists {
+		var err error
+		applied, err = scanApplied(m.pool.Query(ctx, `SELECT version, applied_at FROM schema_migrations`))
+		if err != nil {
+			return err
+		}
+	}
+	if err := m.checkAhead(applied); err != nil {
+		return err
+	}
+	var pending []string
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, fmt.Sprintf("%d_%s", mig.Version, mig.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %s not applied, run `scheduler migrate up`", ErrSchemaBehind, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// checkAhead returns ErrSchemaAhead if applied holds a version newer than Latest.
+func (m *Migrator) checkAhead(applied map[int]time.Time) error {
+	for version := range applied {
+		if version > m.Latest() {
+			return fmt.Errorf("%w: database is at version %d, binary knows up to %d", ErrSchemaAhead, version, m.Latest())
+		}
+	}
+	return nil
+}
+
+// run executes one migration step and updates schema_migrations in a single transaction.
+func run(ctx context.Context, conn *pgx.Conn, sql, record string, args ...any) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Up applies every pending migration up to and including target (0 means all) in version
+// order and returns the ones it applied. It refuses to touch a database that is ahead of
+// the binary.
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *pgx.Conn, applied map[int]time.Time) error {
+		if err := m.checkAhead(applied); err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok || (target > 0 && mig.Version > target) {
+				continue
+			}
+			log.Printf("Applying migration %d_%s", mig.Version, mig.Name)
+			if err := run(ctx, conn, mig.Up, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mig.Version, mig.Name); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the newest steps applied migrations, newest first, and returns the ones it
+// reverted. Migrations the binary does not know cannot be reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *pgx.Conn, applied map[int]time.Time) error {
+		if err := m.checkAhead(applied); err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			log.Printf("Reverting migration %d_%s", mig.Version, mig.Name)
+			if err := run(ctx, conn, mig.Down, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version); err != nil {
+				return fmt.Errorf("revert %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists every known migration with its applied state, followed by applied versions
+// the binary does not know (with an empty Name).
+func (m *Migrator) Status(ctx context.Context) ([]MigrationState, error) {
+	var states []MigrationState
+	err := m.locked(ctx, func(conn *pgx.Conn, applied map[int]time.Time) error {
+		known := make(map[int]bool, len(m.migrations))
+		for _, mig := range m.migrations {
+			at, ok := applied[mig.Version]
+			states = append(states, MigrationState{Migration: mig, Applied: ok, AppliedAt: at})
+			known[mig.Version] = true
+		}
+		var unknown []int
+		for version := range applied {
+			if !known[version] {
+				unknown = append(unknown, version)
+			}
+		}
+		sort.Ints(unknown)
+		for _, version := range unknown {
+			states = append(states, MigrationState{Migration: Migration{Version: version}, Applied: true, AppliedAt: applied[version]})
+		}
+		return nil
+	})
+	return states, err
+}
+
+func (m *Migrator) Close() {
+	m.pool.Close()
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func migrationFS(names ...string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for _, name := range names {
+		fsys["migrations/"+name] = &fstest.MapFile{Data: []byte("-- " + name)}
+	}
+	return fsys
+}
+
+func TestParseMigrationsOrdersByVersion(t *testing.T) {
+	migrations, err := parseMigrations(migrationFS(
+		"10_ten.up.sql", "10_ten.down.sql",
+		"9_nine.up.sql", "9_nine.down.sql",
+		"0001_one.down.sql", "0001_one.up.sql",
+	))
+	if err != nil {
+		t.Fatalf("parseMigrations: %v", err)
+	}
+	var got []string
+	for _, m := range migrations {
+		got = append(got, m.Name)
+	}
+	if strings.Join(got, " ") != "one nine ten" {
+		t.Fatalf("migrations in order %v, want one nine ten", got)
+	}
+	if one := migrations[0]; one.Version != 1 || one.Up != "-- 0001_one.up.sql" || one.Down != "-- 0001_one.down.sql" {
+		t.Fatalf("first migration = %+v", one)
+	}
+}
+
+func TestParseMigrationsRejectsBadSets(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{"stray file", []string{"0001_one.up.sql", "0001_one.down.sql", "README.md"}, "unexpected migration file"},
+		{"no version", []string{"one.up.sql"}, "unexpected migration file"},
+		{"version zero", []string{"0000_zero.up.sql", "0000_zero.down.sql"}, "positive integer"},
+		{"version overflow", []string{"99999999999999999999_big.up.sql"}, "positive integer"},
+		{"missing down", []string{"0001_one.up.sql"}, "needs both"},
+		{"missing up", []string{"0001_one.down.sql"}, "needs both"},
+		{"duplicate version", []string{"0001_one.up.sql", "0001_one.down.sql", "0001_uno.up.sql", "0001_uno.down.sql"}, "two names"},
+		{"duplicate up file", []string{"0001_one.up.sql", "1_one.up.sql", "0001_one.down.sql"}, "more than one up file"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseMigrations(migrationFS(tc.files...))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("parseMigrations = %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Fatalf("migration %d_%s at position %d; versions must be contiguous from 1", m.Version, m.Name, i)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS max_retries,
+    DROP COLUMN IF EXISTS cron_expr,
+    DROP COLUMN IF EXISTS next_run_at,
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS timeout_seconds;
@@ -0,0 +1,7 @@
+-- Retry budget, cron schedules and per-run timeouts.
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS max_retries INTEGER NOT NULL DEFAULT 3,
+    ADD COLUMN IF NOT EXISTS cron_expr TEXT,
+    ADD COLUMN IF NOT EXISTS next_run_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS timezone TEXT,
+    ADD COLUMN IF NOT EXISTS timeout_seconds INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS queue,
+    DROP COLUMN IF EXISTS retry_backoff_seconds,
+    DROP COLUMN IF EXISTS retry_multiplier,
+    DROP COLUMN IF EXISTS retry_max_backoff_seconds,
+    DROP COLUMN IF EXISTS retry_exit_codes;
@@ -0,0 +1,7 @@
+-- Named queues and per-task retry policies.
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS queue TEXT NOT NULL DEFAULT 'default',
+    ADD COLUMN IF NOT EXISTS retry_backoff_seconds INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS retry_multiplier DOUBLE PRECISION NOT NULL DEFAULT 2,
+    ADD COLUMN IF NOT EXISTS retry_max_backoff_seconds INTEGER NOT NULL DEFAULT 300,
+    ADD COLUMN IF NOT EXISTS retry_exit_codes INTEGER[];
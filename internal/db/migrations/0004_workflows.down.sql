@@ -0,0 +1,9 @@
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_check,
+    ADD CONSTRAINT tasks_status_check CHECK (status IN ('PENDING', 'RUNNING', 'SUCCEEDED', 'FAILED')) NOT VALID;
+
+DROP INDEX IF EXISTS tasks_workflow_id_idx;
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS workflow_id,
+    DROP COLUMN IF EXISTS workflow_node;
+DROP TABLE IF EXISTS task_dependencies;
+DROP TABLE IF EXISTS workflows;
@@ -0,0 +1,23 @@
+-- Workflows: groups of tasks with dependency edges. Dependents wait BLOCKED and are SKIPPED
+-- when an upstream task does not succeed. The status check also gains the CANCELLED and
+-- TIMED_OUT states added before migrations existed.
+CREATE TABLE IF NOT EXISTS workflows (
+    id TEXT PRIMARY KEY,
+    name TEXT,
+    created_at BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    depends_on TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, depends_on)
+);
+CREATE INDEX IF NOT EXISTS task_dependencies_depends_on_idx ON task_dependencies (depends_on);
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS workflow_id TEXT REFERENCES workflows(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS workflow_node TEXT;
+CREATE INDEX IF NOT EXISTS tasks_workflow_id_idx ON tasks (workflow_id);
+
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_check,
+    ADD CONSTRAINT tasks_status_check CHECK (status IN ('PENDING', 'RUNNING', 'SUCCEEDED', 'FAILED', 'CANCELLED', 'TIMED_OUT', 'BLOCKED', 'SKIPPED'));
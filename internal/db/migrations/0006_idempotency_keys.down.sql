@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS tasks_idempotency_key_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS idempotency_key;
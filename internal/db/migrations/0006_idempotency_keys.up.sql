@@ -0,0 +1,3 @@
+-- Idempotency keys of SubmitJob; NULL once a key has expired.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS idempotency_key TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_idempotency_key_idx ON tasks (idempotency_key) WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1,19 @@
+ALTER TABLE task_history
+    DROP COLUMN IF EXISTS stdout,
+    DROP COLUMN IF EXISTS stderr,
+    DROP COLUMN IF EXISTS exit_code,
+    DROP COLUMN IF EXISTS signal,
+    DROP COLUMN IF EXISTS wall_time_ms,
+    DROP COLUMN IF EXISTS user_cpu_ms,
+    DROP COLUMN IF EXISTS system_cpu_ms,
+    DROP COLUMN IF EXISTS max_rss_kb;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS stdout,
+    DROP COLUMN IF EXISTS stderr,
+    DROP COLUMN IF EXISTS exit_code,
+    DROP COLUMN IF EXISTS signal,
+    DROP COLUMN IF EXISTS wall_time_ms,
+    DROP COLUMN IF EXISTS user_cpu_ms,
+    DROP COLUMN IF EXISTS system_cpu_ms,
+    DROP COLUMN IF EXISTS max_rss_kb;
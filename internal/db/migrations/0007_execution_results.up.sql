@@ -0,0 +1,20 @@
+-- Structured results: the latest attempt on tasks, every attempt in task_history.
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS stdout TEXT,
+    ADD COLUMN IF NOT EXISTS stderr TEXT,
+    ADD COLUMN IF NOT EXISTS exit_code INTEGER,
+    ADD COLUMN IF NOT EXISTS signal TEXT,
+    ADD COLUMN IF NOT EXISTS wall_time_ms BIGINT,
+    ADD COLUMN IF NOT EXISTS user_cpu_ms BIGINT,
+    ADD COLUMN IF NOT EXISTS system_cpu_ms BIGINT,
+    ADD COLUMN IF NOT EXISTS max_rss_kb BIGINT;
+
+ALTER TABLE task_history
+    ADD COLUMN IF NOT EXISTS stdout TEXT,
+    ADD COLUMN IF NOT EXISTS stderr TEXT,
+    ADD COLUMN IF NOT EXISTS exit_code INTEGER,
+    ADD COLUMN IF NOT EXISTS signal TEXT,
+    ADD COLUMN IF NOT EXISTS wall_time_ms BIGINT,
+    ADD COLUMN IF NOT EXISTS user_cpu_ms BIGINT,
+    ADD COLUMN IF NOT EXISTS system_cpu_ms BIGINT,
+    ADD COLUMN IF NOT EXISTS max_rss_kb BIGINT;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS job_events;
+DROP INDEX IF EXISTS tasks_queue_state_idx;
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS queue_state,
+    DROP COLUMN IF EXISTS queue_since,
+    DROP COLUMN IF EXISTS delayed_until,
+    DROP COLUMN IF EXISTS lease_owner,
+    DROP COLUMN IF EXISTS lease_expires_at;
@@ -0,0 +1,18 @@
+-- Queue state of the Postgres queue backend and the job_events table it streams events from.
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS queue_state TEXT,
+    ADD COLUMN IF NOT EXISTS queue_since TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS delayed_until TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS lease_owner TEXT,
+    ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS tasks_queue_state_idx ON tasks (queue_state, queue) WHERE queue_state IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS job_events (
+    id BIGSERIAL PRIMARY KEY,
+    task_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    data BYTEA,
+    final BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS job_events_task_id_idx ON job_events (task_id, id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS task_history_attempt_idx;
+ALTER TABLE task_history
+    DROP COLUMN IF EXISTS attempt,
+    DROP COLUMN IF EXISTS worker_id,
+    DROP COLUMN IF EXISTS host;
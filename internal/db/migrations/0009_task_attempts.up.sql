@@ -0,0 +1,8 @@
+-- Per-attempt history: each run of a task is one task_history row, opened when a worker
+-- claims the task and closed when the run ends. Rows written before this migration have no
+-- attempt number and are not reported.
+ALTER TABLE task_history
+    ADD COLUMN IF NOT EXISTS attempt INTEGER,
+    ADD COLUMN IF NOT EXISTS worker_id TEXT,
+    ADD COLUMN IF NOT EXISTS host TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS task_history_attempt_idx ON task_history (task_id, attempt) WHERE attempt IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS leader_epoch;
+DROP TABLE IF EXISTS leader_fence;
@@ -0,0 +1,10 @@
+-- Fencing for leader-only writes: leader_fence holds the newest leader epoch, and tasks
+-- record the epoch of the leader that last scheduled them.
+CREATE TABLE IF NOT EXISTS leader_fence (
+    id SMALLINT PRIMARY KEY CHECK (id = 1),
+    epoch BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+INSERT INTO leader_fence (id, epoch) VALUES (1, 0) ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS leader_epoch BIGINT NOT NULL DEFAULT 0;
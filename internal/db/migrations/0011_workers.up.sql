@@ -0,0 +1,8 @@
+-- Worker registry of the Postgres queue backend. A worker is alive while its lease has not
+-- expired; it renews the lease with every heartbeat.
+CREATE TABLE IF NOT EXISTS workers (
+    id TEXT PRIMARY KEY,
+    info JSONB NOT NULL,
+    last_seen TIMESTAMPTZ NOT NULL DEFAULT now(),
+    lease_expires_at TIMESTAMPTZ NOT NULL
+);
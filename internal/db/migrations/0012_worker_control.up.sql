@@ -0,0 +1,2 @@
+-- Run state a worker was asked to take (RUNNING, PAUSED or DRAINING; '' = none).
+ALTER TABLE workers ADD COLUMN IF NOT EXISTS control TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Entries written by a dead-letter replay also take the task out of its DLQ.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS replay BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+-- Identity of the leader that registered the current epoch, so the Postgres election can
+-- reserve an epoch that only its own term may register.
+ALTER TABLE leader_fence ADD COLUMN IF NOT EXISTS holder TEXT NOT NULL DEFAULT '';
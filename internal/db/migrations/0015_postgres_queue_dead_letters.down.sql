@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tasks_dead_since_idx;
+UPDATE tasks SET queue_state = 'dead', queue_since = dead_since WHERE dead_since IS NOT NULL AND queue_state IS NULL;
+ALTER TABLE tasks DROP COLUMN IF EXISTS dead_since;
@@ -0,0 +1,5 @@
+-- Dead letters of the Postgres queue backend get their own column, so a task can be in its
+-- DLQ and pending again at the same time (e.g. the next cron run), like in Redis.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS dead_since TIMESTAMPTZ;
+UPDATE tasks SET dead_since = queue_since, queue_state = NULL WHERE queue_state = 'dead';
+CREATE INDEX IF NOT EXISTS tasks_dead_since_idx ON tasks (queue, dead_since) WHERE dead_since IS NOT NULL;
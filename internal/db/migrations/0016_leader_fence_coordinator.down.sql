@@ -0,0 +1 @@
+ALTER TABLE leader_fence DROP COLUMN IF EXISTS coordinator;
@@ -0,0 +1,3 @@
+-- Election backend (etcd or postgres) that registered the current epoch, so a leader of
+-- another backend whose epochs lag behind can tell a foreign fence from a newer leader.
+ALTER TABLE leader_fence ADD COLUMN IF NOT EXISTS coordinator TEXT NOT NULL DEFAULT '';
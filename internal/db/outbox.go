@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// OUTBOX_RETRY_DELAY is how long a failed outbox entry waits before it is published again.
+const OUTBOX_RETRY_DELAY = 5 * time.Second
+
+// OutboxEntry asks for a task to be pushed to its queue. Entries are written in the same
+// transaction that makes the task runnable and deleted once published.
+type OutboxEntry struct {
+	ID       int64
+	TaskID   string
+	Queue    string
+	Priority int32
+	Attempts int32
+	// Replay marks an entry written by ReplayJob; publishing it also takes the task out of
+	// its dead-letter queue.
+	Replay bool
+}
+
+// enqueueOutbox adds an outbox entry for every given task, inside the caller's transaction.
+func enqueueOutbox(ctx context.Context, tx pgx.Tx, ids ...string) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO outbox (task_id, queue, priority) SELECT id, queue, priority FROM tasks WHERE id = ANY($1)`, ids)
+	return err
+}
+
+// enqueueReplay adds an outbox entry that moves task id from its DLQ back to its queue.
+func enqueueReplay(ctx context.Context, tx pgx.Tx, id string) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO outbox (task_id, queue, priority, replay) SELECT id, queue, priority, true FROM tasks WHERE id = $1`, id)
+	return err
+}
+
+// RelayOutbox hands up to limit due outbox entries to publish, oldest first. Entries that
+// were published are deleted; the others are retried after OUTBOX_RETRY_DELAY. Rows are
+// locked with SKIP LOCKED so several relays can run at once. An entry may be published more
+// than once if the process dies before the deletion commits. It returns the number published.
+func (m *DBManager) RelayOutbox(limit int, publish func(e *OutboxEntry) error) (int, error) {
+	ctx := context.Background()
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, task_id, queue, priority, attempts, replay FROM outbox
+		 WHERE available_at <= now()
+		 ORDER BY id
+		 LIMIT $1
+		 FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, err
+	}
+	var entries []*OutboxEntry
+	for rows.Next() {
+		e := &OutboxEntry{}
+		if err := rows.Scan(&e.ID, &e.TaskID, &e.Queue, &e.Priority, &e.Attempts, &e.Replay); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var done []int64
+	for _, e := range entries {
+		if err := publish(e); err != nil {
+			if _, err := tx.Exec(ctx,
+				`UPDATE outbox SET attempts = attempts + 1, last_error = $2, available_at = now() + $3 * interval '1 millisecond' WHERE id = $1`,
+				e.ID, err.Error(), OUTBOX_RETRY_DELAY.Milliseconds()); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		done = append(done, e.ID)
+	}
+	if len(done) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM outbox WHERE id = ANY($1)`, done); err != nil {
+			return 0, err
+		}
+	}
+	return len(done), tx.Commit(ctx)
+}
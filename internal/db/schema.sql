@@ -1,19 +1,75 @@
--- PostgreSQL schema for centralized database
-CREATE TABLE IF NOT EXISTS tasks (
+-- PostgreSQL schema after all migrations in migrations/, for reference only. The
+-- migrations are the source of truth; update this file when adding one.
+-- Apply with: scheduler migrate up
+
+CREATE TABLE schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE workflows (
+    id TEXT PRIMARY KEY,
+    name TEXT,
+    created_at BIGINT NOT NULL
+);
+
+CREATE TABLE tasks (
     id TEXT PRIMARY KEY,
     name TEXT,
     args JSONB,
     command TEXT,
     execute_at TIMESTAMPTZ,
-    status TEXT NOT NULL CHECK (status IN ('PENDING', 'RUNNING', 'SUCCEEDED', 'FAILED')),
+    status TEXT NOT NULL CONSTRAINT tasks_status_check
+        CHECK (status IN ('PENDING', 'RUNNING', 'SUCCEEDED', 'FAILED', 'CANCELLED', 'TIMED_OUT', 'BLOCKED', 'SKIPPED')),
     retries INTEGER NOT NULL DEFAULT 0,
     priority INTEGER NOT NULL DEFAULT 0,
     output TEXT,
     created_at BIGINT NOT NULL,
-    updated_at BIGINT NOT NULL
+    updated_at BIGINT NOT NULL,
+    -- 0002_scheduling
+    max_retries INTEGER NOT NULL DEFAULT 3,
+    cron_expr TEXT,
+    next_run_at TIMESTAMPTZ,
+    timezone TEXT,
+    timeout_seconds INTEGER NOT NULL DEFAULT 0,
+    -- 0003_queues_and_retry_policy
+    queue TEXT NOT NULL DEFAULT 'default',
+    retry_backoff_seconds INTEGER NOT NULL DEFAULT 1,
+    retry_multiplier DOUBLE PRECISION NOT NULL DEFAULT 2,
+    retry_max_backoff_seconds INTEGER NOT NULL DEFAULT 300,
+    retry_exit_codes INTEGER[],
+    -- 0004_workflows
+    workflow_id TEXT REFERENCES workflows(id) ON DELETE CASCADE,
+    workflow_node TEXT,
+    -- 0006_idempotency_keys
+    idempotency_key TEXT,
+    -- 0007_execution_results
+    stdout TEXT,
+    stderr TEXT,
+    exit_code INTEGER,
+    signal TEXT,
+    wall_time_ms BIGINT,
+    user_cpu_ms BIGINT,
+    system_cpu_ms BIGINT,
+    max_rss_kb BIGINT,
+    -- 0008_postgres_queue
+    queue_state TEXT,
+    queue_since TIMESTAMPTZ,
+    delayed_until TIMESTAMPTZ,
+    lease_owner TEXT,
+    lease_expires_at TIMESTAMPTZ,
+    -- 0010_leader_fencing
+    leader_epoch BIGINT NOT NULL DEFAULT 0,
+    -- 0015_postgres_queue_dead_letters
+    dead_since TIMESTAMPTZ
 );
+CREATE INDEX tasks_workflow_id_idx ON tasks (workflow_id);
+CREATE UNIQUE INDEX tasks_idempotency_key_idx ON tasks (idempotency_key) WHERE idempotency_key IS NOT NULL;
+CREATE INDEX tasks_queue_state_idx ON tasks (queue_state, queue) WHERE queue_state IS NOT NULL;
+CREATE INDEX tasks_dead_since_idx ON tasks (queue, dead_since) WHERE dead_since IS NOT NULL;
 
-CREATE TABLE IF NOT EXISTS task_history (
+CREATE TABLE task_history (
     id BIGSERIAL PRIMARY KEY,
     task_id TEXT NOT NULL,
     status TEXT NOT NULL,
@@ -21,5 +77,63 @@ CREATE TABLE IF NOT EXISTS task_history (
     end_time TIMESTAMPTZ,
     result TEXT,
     created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    stdout TEXT,
+    stderr TEXT,
+    exit_code INTEGER,
+    signal TEXT,
+    wall_time_ms BIGINT,
+    user_cpu_ms BIGINT,
+    system_cpu_ms BIGINT,
+    max_rss_kb BIGINT,
+    attempt INTEGER,
+    worker_id TEXT,
+    host TEXT,
     FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
-);
\ No newline at end of file
+);
+CREATE UNIQUE INDEX task_history_attempt_idx ON task_history (task_id, attempt) WHERE attempt IS NOT NULL;
+
+CREATE TABLE task_dependencies (
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    depends_on TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, depends_on)
+);
+CREATE INDEX task_dependencies_depends_on_idx ON task_dependencies (depends_on);
+
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    queue TEXT NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    replay BOOLEAN NOT NULL DEFAULT false
+);
+CREATE INDEX outbox_available_at_idx ON outbox (available_at);
+
+CREATE TABLE job_events (
+    id BIGSERIAL PRIMARY KEY,
+    task_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    data BYTEA,
+    final BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX job_events_task_id_idx ON job_events (task_id, id);
+
+CREATE TABLE leader_fence (
+    id SMALLINT PRIMARY KEY CHECK (id = 1),
+    epoch BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    holder TEXT NOT NULL DEFAULT '',
+    coordinator TEXT NOT NULL DEFAULT ''
+);
+INSERT INTO leader_fence (id, epoch) VALUES (1, 0);
+
+CREATE TABLE workers (
+    id TEXT PRIMARY KEY,
+    info JSONB NOT NULL,
+    last_seen TIMESTAMPTZ NOT NULL DEFAULT now(),
+    lease_expires_at TIMESTAMPTZ NOT NULL,
+    control TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,46 @@
+package db
+
+import "time"
+
+// Store keeps tasks, workflows and the outbox. DBManager stores them in Postgres and
+// MemoryStore in process memory.
+type Store interface {
+	CreateJob(job *Job, keySince int64) (*Job, error)
+	GetJob(id string) (*Job, error)
+	GetJobs(ids []string) (map[string]*Job, error)
+	ListJobs(f JobFilter) ([]*Job, error)
+
+	ClaimJob(id, workerID, host string) (int32, error)
+	UpdateJobStatus(id, status string, output string) error
+	FinishAttempt(id string, attempt int32, status, output string, res *ExecutionResult) error
+	CancelJob(id string) (bool, error)
+	ReplayJob(id string) (bool, error)
+	IncrementRetry(id string) (int32, int32, error)
+	ResetToPending(id string, output string) error
+	TouchJob(id string) error
+	GetJobHistory(id string) ([]*Attempt, error)
+
+	GetDueTaskIDs(limit int) ([]string, error)
+	EnqueueDueTask(job *Job, nextRun time.Time, epoch int64) (bool, error)
+	MarkStaleRunningJobsFailed(cutoffSeconds, epoch int64) (int64, error)
+	ReclaimRunningJob(id, output string, epoch int64) (string, int32, int32, error)
+	RelayOutbox(limit int, publish func(e *OutboxEntry) error) (int, error)
+
+	CreateWorkflow(wf *Workflow, jobs []*Job, deps map[string][]string) error
+	GetWorkflow(id string) (*Workflow, error)
+	WorkflowJobs(id string) ([]*Job, error)
+	WorkflowDependencies(id string) (map[string][]string, error)
+	ReleaseBlockedTasks(parentID string) ([]string, error)
+	ReleaseReadyTasks(epoch int64) ([]string, error)
+	SkipDependents(id string) (int64, error)
+	SkipStrandedTasks(graceSeconds, epoch int64) (int64, error)
+
+	AdvanceLeaderEpoch(epoch int64, holder, coordinator string) error
+
+	Close() error
+}
+
+var (
+	_ Store = (*DBManager)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
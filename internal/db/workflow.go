@@ -0,0 +1,234 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Workflow groups tasks whose dependencies are stored in task_dependencies.
+type Workflow struct {
+	ID        string
+	Name      string
+	CreatedAt int64
+}
+
+// CreateWorkflow inserts wf, its tasks and their dependency edges in one transaction. deps
+// maps a task ID to the IDs it depends on; those tasks start BLOCKED, the rest PENDING with
+// an outbox entry.
+func (m *DBManager) CreateWorkflow(wf *Workflow, jobs []*Job, deps map[string][]string) error {
+	ctx := context.Background()
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `INSERT INTO workflows (id, name, created_at) VALUES ($1, $2, $3)`, wf.ID, wf.Name, wf.CreatedAt); err != nil {
+		return err
+	}
+	var roots []string
+	for _, job := range jobs {
+		status := "PENDING"
+		if len(deps[job.ID]) > 0 {
+			status = "BLOCKED"
+		} else {
+			roots = append(roots, job.ID)
+		}
+		if _, err := insertJob(ctx, tx, job, status); err != nil {
+			return err
+		}
+	}
+	for id, parents := range deps {
+		for _, parent := range parents {
+			if _, err := tx.Exec(ctx, `INSERT INTO task_dependencies (task_id, depends_on) VALUES ($1, $2)`, id, parent); err != nil {
+				return err
+			}
+		}
+	}
+	if err := enqueueOutbox(ctx, tx, roots...); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (m *DBManager) GetWorkflow(id string) (*Workflow, error) {
+	ctx := context.Background()
+	wf := &Workflow{}
+	err := m.pool.QueryRow(ctx, `SELECT id, COALESCE(name, ''), created_at FROM workflows WHERE id = $1`, id).Scan(&wf.ID, &wf.Name, &wf.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return wf, nil
+}
+
+// WorkflowJobs returns the tasks of a workflow in submission order.
+func (m *DBManager) WorkflowJobs(id string) ([]*Job, error) {
+	ctx := context.Background()
+	rows, err := m.pool.Query(ctx, `SELECT `+jobColumns+` FROM tasks WHERE workflow_id = $1 ORDER BY created_at, workflow_node`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var jobs []*Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// WorkflowDependencies maps each task of a workflow to the task IDs it depends on.
+func (m *DBManager) WorkflowDependencies(id string) (map[string][]string, error) {
+	ctx := context.Background()
+	rows, err := m.pool.Query(ctx,
+		`SELECT d.task_id, d.depends_on FROM task_dependencies d JOIN tasks t ON t.id = d.task_id
+		 WHERE t.workflow_id = $1 ORDER BY d.task_id, d.depends_on`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	deps := make(map[string][]string)
+	for rows.Next() {
+		var taskID, parent string
+		if err := rows.Scan(&taskID, &parent); err != nil {
+			return nil, err
+		}
+		deps[taskID] = append(deps[taskID], parent)
+	}
+	return deps, rows.Err()
+}
+
+// ReleaseBlockedTasks moves BLOCKED tasks whose dependencies have all SUCCEEDED to PENDING,
+// adds their outbox entries in the same statement and returns their IDs. With a non-empty
+// parentID only direct dependents of that task are considered. Each task is released once.
+func (m *DBManager) ReleaseBlockedTasks(parentID string) ([]string, error) {
+	ctx := context.Background()
+	rows, err := m.pool.Query(ctx,
+		`WITH released AS (
+		   UPDATE tasks t SET status='PENDING', updated_at=$1
+		   WHERE t.status='BLOCKED'
+		     AND ($2::text = '' OR t.id IN (SELECT task_id FROM task_dependencies WHERE depends_on = $2))
+		     AND NOT EXISTS (
+		       SELECT 1 FROM task_dependencies d JOIN tasks p ON p.id = d.depends_on
+		       WHERE d.task_id = t.id AND p.status <> 'SUCCEEDED'
+		     )
+		   RETURNING t.id, t.queue, t.priority
+		 ), queued AS (
+		   INSERT INTO outbox (task_id, queue, priority) SELECT id, queue, priority FROM released
+		 )
+		 SELECT id FROM released`,
+		time.Now().Unix(), parentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ReleaseReadyTasks is the leader sweep of ReleaseBlockedTasks over every BLOCKED task, for
+// releases a worker did not make. It fails with ErrStaleLeader if epoch has been superseded.
+func (m *DBManager) ReleaseReadyTasks(epoch int64) ([]string, error) {
+	ctx := context.Background()
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	if err := checkEpoch(ctx, tx, epoch); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx,
+		`WITH released AS (
+		   UPDATE tasks t SET status='PENDING', updated_at=$1, leader_epoch=$2
+		   WHERE t.status='BLOCKED' AND t.leader_epoch <= $2
+		     AND NOT EXISTS (
+		       SELECT 1 FROM task_dependencies d JOIN tasks p ON p.id = d.depends_on
+		       WHERE d.task_id = t.id AND p.status <> 'SUCCEEDED'
+		     )
+		   RETURNING t.id, t.queue, t.priority
+		 ), queued AS (
+		   INSERT INTO outbox (task_id, queue, priority) SELECT id, queue, priority FROM released
+		 )
+		 SELECT id FROM released`,
+		time.Now().Unix(), epoch,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, tx.Commit(ctx)
+}
+
+// SkipDependents marks every BLOCKED task downstream of id as SKIPPED, e.g. once id has
+// failed for good. It returns the number of skipped tasks.
+func (m *DBManager) SkipDependents(id string) (int64, error) {
+	ctx := context.Background()
+	cmdTag, err := m.pool.Exec(ctx,
+		`WITH RECURSIVE downstream AS (
+		   SELECT task_id FROM task_dependencies WHERE depends_on = $1
+		   UNION
+		   SELECT d.task_id FROM task_dependencies d JOIN downstream x ON d.depends_on = x.task_id
+		 )
+		 UPDATE tasks SET status='SKIPPED', output='Skipped: upstream job ' || $1::text || ' did not succeed', updated_at=$2
+		 WHERE status='BLOCKED' AND id IN (SELECT task_id FROM downstream)`,
+		id, time.Now().Unix(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+// SkipStrandedTasks marks BLOCKED tasks SKIPPED when a dependency was cancelled or skipped,
+// or has been FAILED or TIMED_OUT for longer than graceSeconds. The grace period keeps it
+// from acting on a failed attempt that is about to be retried. It fails with ErrStaleLeader
+// if epoch has been superseded.
+func (m *DBManager) SkipStrandedTasks(graceSeconds, epoch int64) (int64, error) {
+	ctx := context.Background()
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	if err := checkEpoch(ctx, tx, epoch); err != nil {
+		return 0, err
+	}
+
+	now := time.Now().Unix()
+	cmdTag, err := tx.Exec(ctx,
+		`UPDATE tasks t SET status='SKIPPED', output='Skipped: upstream job ' || p.id || ' finished with status ' || p.status, updated_at=$1, leader_epoch=$3
+		 FROM task_dependencies d JOIN tasks p ON p.id = d.depends_on
+		 WHERE d.task_id = t.id AND t.status='BLOCKED' AND t.leader_epoch <= $3
+		   AND (p.status IN ('CANCELLED', 'SKIPPED') OR (p.status IN ('FAILED', 'TIMED_OUT') AND p.updated_at < $1 - $2))`,
+		now, graceSeconds, epoch,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return cmdTag.RowsAffected(), tx.Commit(ctx)
+}
@@ -0,0 +1,104 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// BACKEND_REDIS keeps queues in Redis (QueueManager).
+	BACKEND_REDIS = "redis"
+	// BACKEND_POSTGRES keeps queues in the tasks table (PostgresQueue), so no Redis is needed.
+	BACKEND_POSTGRES = "postgres"
+	// BACKEND_STREAMS keeps queues in Redis Streams with a consumer group (StreamQueue).
+	BACKEND_STREAMS = "streams"
+)
+
+// Queue moves job IDs between the pending, processing, delayed and dead-letter states of
+// named queues, and carries the leases workers hold on the jobs they process.
+type Queue interface {
+	// PushJob makes jobId pending in queue; PopJob moves the best pending job of the first
+	// non-empty queue in queues to processing, or returns ErrQueueTimeout.
+	PushJob(ctx context.Context, queue, jobId string, priority int32) error
+	PopJob(ctx context.Context, queues []string) (jobId string, queue string, err error)
+	AckProcessing(ctx context.Context, queue, jobId string) error
+	DropDuplicate(ctx context.Context, queue, jobId string) error
+	RequeueFromProcessing(ctx context.Context, queue, jobId string) error
+	RemovePending(ctx context.Context, queue, jobId string) error
+	Queues(ctx context.Context) ([]string, error)
+	// Deliveries returns how often the job's current queue entry was handed to a worker,
+	// or 0 if the backend does not count deliveries.
+	Deliveries(ctx context.Context, queue, jobId string) (int64, error)
+
+	DelayFromProcessing(ctx context.Context, queue, jobId string, until time.Time) error
+	PromoteDelayed(ctx context.Context, queue string, now time.Time, epoch int64) (int, error)
+
+	MoveToDLQ(ctx context.Context, queue, jobId string) error
+	DeadLetters(ctx context.Context, queue string) ([]string, error)
+	IsDeadLetter(ctx context.Context, queue, jobId string) (bool, error)
+	ReplayDeadLetter(ctx context.Context, queue, jobId string, priority int32) (bool, error)
+	RemoveDeadLetter(ctx context.Context, queue, jobId string) (bool, error)
+
+	RenewLease(ctx context.Context, jobId, workerId string) error
+	ReleaseLease(ctx context.Context, jobId string) error
+	OrphanedJobs(ctx context.Context) ([]JobRef, error)
+}
+
+// Canceller broadcasts cancellation requests to the workers running the jobs.
+type Canceller interface {
+	PublishCancel(ctx context.Context, jobId string) error
+	SubscribeCancellations(ctx context.Context) (<-chan string, error)
+}
+
+// Registry keeps the live workers and the run states they were asked to take.
+type Registry interface {
+	// RegisterWorker records a worker and renews its lease; Workers lists the registered
+	// workers, including those whose lease expired recently.
+	RegisterWorker(ctx context.Context, info WorkerInfo) error
+	DeregisterWorker(ctx context.Context, workerId string) error
+	Workers(ctx context.Context) ([]*WorkerInfo, error)
+	// SetWorkerControl asks a worker to take a run state such as WORKER_DRAINING; workers
+	// poll WorkerControl for it.
+	SetWorkerControl(ctx context.Context, workerId, control string) error
+	WorkerControl(ctx context.Context, workerId string) (string, error)
+}
+
+// Events carries the output and status changes of jobs for clients that follow them.
+type Events interface {
+	AppendJobOutput(ctx context.Context, jobId, stream string, data []byte) error
+	PublishJobStatus(ctx context.Context, jobId, status string, final bool) error
+	LastJobEventID(ctx context.Context, jobId string) (string, error)
+	ReadJobEvents(ctx context.Context, jobId, after string, block time.Duration) ([]JobEvent, error)
+}
+
+// Backend is everything a server or worker needs from one queue backend.
+type Backend interface {
+	Queue
+	Canceller
+	Registry
+	Events
+
+	Close() error
+}
+
+// Open connects to the queue backend named by backend: Redis at redisAddr, or the Postgres
+// database at dsn. An empty backend means BACKEND_REDIS.
+func Open(backend, redisAddr, dsn string) (Backend, error) {
+	switch backend {
+	case "", BACKEND_REDIS:
+		return NewQueueManager(redisAddr)
+	case BACKEND_POSTGRES:
+		return NewPostgresQueue(dsn)
+	case BACKEND_STREAMS:
+		return NewStreamQueue(redisAddr)
+	}
+	return nil, fmt.Errorf("unknown queue backend %q, want %q, %q or %q", backend, BACKEND_REDIS, BACKEND_POSTGRES, BACKEND_STREAMS)
+}
+
+var (
+	_ Backend = (*QueueManager)(nil)
+	_ Backend = (*PostgresQueue)(nil)
+	_ Backend = (*StreamQueue)(nil)
+	_ Backend = (*MemoryQueue)(nil)
+)
@@ -0,0 +1,79 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// PROMOTE_BATCH bounds how many delayed jobs one PromoteDelayed call moves.
+	PROMOTE_BATCH = 100
+	// LEADER_EPOCH_KEY holds the newest leader epoch PromoteDelayed was called with.
+	LEADER_EPOCH_KEY = "leader_epoch"
+)
+
+// ErrStaleLeader is returned by PromoteDelayed when called with an epoch older than the
+// newest one seen, i.e. by a leader that has been replaced. It mirrors db.ErrStaleLeader.
+var ErrStaleLeader = errors.New("a newer leader has taken over")
+
+// fenceLua rejects a call made with epoch ARGV[4] by returning -1 if KEYS[4] holds a newer
+// epoch, and records ARGV[4] otherwise.
+const fenceLua = `
+local fence = tonumber(redis.call('GET', KEYS[4]) or '0')
+if fence > tonumber(ARGV[4]) then
+  return -1
+end
+redis.call('SET', KEYS[4], ARGV[4])
+`
+
+// promoteScript moves delayed jobs due at ARGV[1] (Unix ms) into the pending set, scoring
+// them like a fresh push with the priority recorded in KEYS[3].
+var promoteScript = redis.NewScript(fenceLua + `
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[3]))
+for _, id in ipairs(ids) do
+  local prio = tonumber(redis.call('HGET', KEYS[3], id) or '0') or 0
+  redis.call('ZREM', KEYS[1], id)
+  redis.call('ZADD', KEYS[2], 'NX', tonumber(ARGV[1]) - prio * tonumber(ARGV[2]), id)
+end
+return #ids
+`)
+
+// DelayFromProcessing moves jobId from the processing queue to the delayed set of queue,
+// where it waits until PromoteDelayed finds it due at the given time.
+func (m *QueueManager) DelayFromProcessing(ctx context.Context, queue, jobId string, until time.Time) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, processingKey(queue), 1, jobId)
+		pipe.ZAdd(ctx, delayedKey(queue), redis.Z{Score: float64(until.UnixMilli()), Member: jobId})
+		return nil
+	})
+	return err
+}
+
+// PromoteDelayed moves delayed jobs of queue whose time has come back to its pending set
+// and returns how many were moved. epoch is the fencing token of the calling leader; it
+// fails with ErrStaleLeader once a newer one has been used.
+func (m *QueueManager) PromoteDelayed(ctx context.Context, queue string, now time.Time, epoch int64) (int, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return 0, err
+	}
+	keys := []string{delayedKey(queue), pendingKey(queue), JOB_PRIORITIES_HASH, LEADER_EPOCH_KEY}
+	n, err := promoteScript.Run(ctx, client, keys, now.UnixMilli(), PRIORITY_AGING_STEP.Milliseconds(), PROMOTE_BATCH, epoch).Int()
+	return fencedCount(n, err, epoch)
+}
+
+// fencedCount maps the -1 returned by a script starting with fenceLua to ErrStaleLeader.
+func fencedCount(n int, err error, epoch int64) (int, error) {
+	if err == nil && n < 0 {
+		return 0, fmt.Errorf("%w: epoch %d is outdated", ErrStaleLeader, epoch)
+	}
+	return n, err
+}
@@ -0,0 +1,63 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// replayScript moves a job from a DLQ list back into the pending set, doing nothing if the
+// job is no longer dead-lettered.
+var replayScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+  return 0
+end
+redis.call('HSET', KEYS[3], ARGV[1], ARGV[3])
+redis.call('ZADD', KEYS[2], 'NX', ARGV[2], ARGV[1])
+return 1
+`)
+
+// DeadLetters returns the IDs in the DLQ of queue, oldest first.
+func (m *QueueManager) DeadLetters(ctx context.Context, queue string) ([]string, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.LRange(ctx, dlqKey(queue), 0, -1).Result()
+}
+
+// IsDeadLetter reports whether jobId is in the DLQ of queue.
+func (m *QueueManager) IsDeadLetter(ctx context.Context, queue, jobId string) (bool, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return false, err
+	}
+	_, err = client.LPos(ctx, dlqKey(queue), jobId, redis.LPosArgs{}).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ReplayDeadLetter moves jobId from the DLQ of queue back to its pending set. It returns
+// false if the job was not dead-lettered.
+func (m *QueueManager) ReplayDeadLetter(ctx context.Context, queue, jobId string, priority int32) (bool, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return false, err
+	}
+	keys := []string{dlqKey(queue), pendingKey(queue), JOB_PRIORITIES_HASH}
+	n, err := replayScript.Run(ctx, client, keys, jobId, pendingScore(time.Now(), priority), priority).Int()
+	return n == 1, err
+}
+
+// RemoveDeadLetter drops jobId from the DLQ of queue. It returns false if it was not there.
+func (m *QueueManager) RemoveDeadLetter(ctx context.Context, queue, jobId string) (bool, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := client.LRem(ctx, dlqKey(queue), 0, jobId).Result()
+	return n > 0, err
+}
@@ -0,0 +1,113 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// JOB_EVENTS_PREFIX keys the per-job Redis stream of status changes and output chunks.
+	JOB_EVENTS_PREFIX = "job_events:"
+	// JOB_EVENTS_MAXLEN caps each stream (approximately); older entries are trimmed.
+	JOB_EVENTS_MAXLEN = 10000
+	// JOB_EVENTS_TTL is how long a stream is kept after its last entry.
+	JOB_EVENTS_TTL = 24 * time.Hour
+	// JOB_EVENTS_BATCH is the most entries one ReadJobEvents call returns.
+	JOB_EVENTS_BATCH = 500
+
+	EVENT_STATUS = "status"
+	EVENT_STDOUT = "stdout"
+	EVENT_STDERR = "stderr"
+)
+
+// JobEvent is one entry of a job's event stream.
+type JobEvent struct {
+	ID   string // stream entry ID, usable as the cursor of the next read
+	Kind string // EVENT_STATUS, EVENT_STDOUT or EVENT_STDERR
+	Data string // the status, or a chunk of output
+	// Final is set on status events after which the job will not run again on its own.
+	Final bool
+	Time  time.Time
+}
+
+func jobEventsKey(jobId string) string { return JOB_EVENTS_PREFIX + jobId }
+
+func (m *QueueManager) appendJobEvent(ctx context.Context, jobId string, values map[string]interface{}) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return err
+	}
+	key := jobEventsKey(jobId)
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{Stream: key, MaxLen: JOB_EVENTS_MAXLEN, Approx: true, Values: values})
+		pipe.Expire(ctx, key, JOB_EVENTS_TTL)
+		return nil
+	})
+	return err
+}
+
+// AppendJobOutput adds a chunk of a running job's stdout or stderr to its event stream.
+func (m *QueueManager) AppendJobOutput(ctx context.Context, jobId, stream string, data []byte) error {
+	return m.appendJobEvent(ctx, jobId, map[string]interface{}{"kind": stream, "data": data})
+}
+
+// PublishJobStatus records a status change in the job's event stream. final marks statuses
+// the job will not leave on its own, so watchers can stop.
+func (m *QueueManager) PublishJobStatus(ctx context.Context, jobId, status string, final bool) error {
+	return m.appendJobEvent(ctx, jobId, map[string]interface{}{"kind": EVENT_STATUS, "data": status, "final": strconv.FormatBool(final)})
+}
+
+// LastJobEventID returns the ID of the newest entry in the job's event stream, or "0" if
+// the stream is empty, for reading only events that follow.
+func (m *QueueManager) LastJobEventID(ctx context.Context, jobId string) (string, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return "", err
+	}
+	msgs, err := client.XRevRangeN(ctx, jobEventsKey(jobId), "+", "-", 1).Result()
+	if err != nil || len(msgs) == 0 {
+		return "0", err
+	}
+	return msgs[0].ID, nil
+}
+
+// ReadJobEvents returns up to JOB_EVENTS_BATCH entries after the given entry ID ("0" for the
+// start). With block > 0 it waits up to that long for new entries and returns none on timeout.
+func (m *QueueManager) ReadJobEvents(ctx context.Context, jobId, after string, block time.Duration) ([]JobEvent, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return nil, err
+	}
+	args := &redis.XReadArgs{Streams: []string{jobEventsKey(jobId), after}, Count: JOB_EVENTS_BATCH, Block: -1}
+	if block > 0 {
+		args.Block = block
+	}
+	streams, err := client.XRead(ctx, args).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var events []JobEvent
+	for _, st := range streams {
+		for _, msg := range st.Messages {
+			ev := JobEvent{ID: msg.ID}
+			ev.Kind, _ = msg.Values["kind"].(string)
+			ev.Data, _ = msg.Values["data"].(string)
+			final, _ := msg.Values["final"].(string)
+			ev.Final = final == "true"
+			if ms, _, ok := strings.Cut(msg.ID, "-"); ok {
+				if n, err := strconv.ParseInt(ms, 10, 64); err == nil {
+					ev.Time = time.UnixMilli(n)
+				}
+			}
+			events = append(events, ev)
+		}
+	}
+	return events, nil
+}
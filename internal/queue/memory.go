@@ -0,0 +1,494 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memoryQueue holds the state of one named queue of MemoryQueue.
+type memoryQueue struct {
+	pending    map[string]float64 // job ID -> pendingScore
+	processing []string           // may hold a job twice, like the Redis list
+	delayed    map[string]time.Time
+	dead       []string
+}
+
+// MemoryQueue is a Queue that keeps everything in process memory, for tests and the
+// standalone mode, where the server and its workers share one process. It follows the
+// semantics of QueueManager, including priority aging and leases, but nothing survives a
+// restart.
+type MemoryQueue struct {
+	mu         sync.Mutex
+	queues     map[string]*memoryQueue
+	priorities map[string]int32
+	leases     map[string]time.Time // job ID -> lease expiry
+	events     map[string][]JobEvent
+	eventSeq   int64
+	cancels    []chan string
+	workers    map[string]*WorkerInfo
+	workerTTL  map[string]time.Time // worker ID -> lease expiry
+	controls   map[string]string    // worker ID -> requested run state
+	// leaderEpoch is the newest epoch PromoteDelayed was called with.
+	leaderEpoch int64
+	// wake is closed and replaced whenever a job becomes pending or an event is added.
+	wake chan struct{}
+}
+
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		queues:     make(map[string]*memoryQueue),
+		priorities: make(map[string]int32),
+		leases:     make(map[string]time.Time),
+		events:     make(map[string][]JobEvent),
+		workers:    make(map[string]*WorkerInfo),
+		workerTTL:  make(map[string]time.Time),
+		controls:   make(map[string]string),
+		wake:       make(chan struct{}),
+	}
+}
+
+// queue returns the state of the named queue, creating it. Callers must hold mu.
+func (m *MemoryQueue) queue(name string) *memoryQueue {
+	name = queueName(name)
+	q, ok := m.queues[name]
+	if !ok {
+		q = &memoryQueue{pending: make(map[string]float64), delayed: make(map[string]time.Time)}
+		m.queues[name] = q
+	}
+	return q
+}
+
+// notify wakes every waiter. Callers must hold mu.
+func (m *MemoryQueue) notify() {
+	close(m.wake)
+	m.wake = make(chan struct{})
+}
+
+// removeOne drops the first occurrence of id from ids.
+func removeOne(ids []string, id string) ([]string, bool) {
+	if i := slices.Index(ids, id); i >= 0 {
+		return slices.Delete(ids, i, i+1), true
+	}
+	return ids, false
+}
+
+// PushJob adds jobId to the pending set of queue. Pushing a job that is already pending keeps
+// its original position.
+func (m *MemoryQueue) PushJob(ctx context.Context, queue, jobId string, priority int32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q := m.queue(queue)
+	m.priorities[jobId] = priority
+	if _, ok := q.pending[jobId]; !ok {
+		q.pending[jobId] = pendingScore(time.Now(), priority)
+	}
+	m.notify()
+	return nil
+}
+
+// PopJob moves the lowest-scored pending job of the first non-empty queue in queues to its
+// processing list, waiting up to POP_TIMEOUT for one to arrive.
+func (m *MemoryQueue) PopJob(ctx context.Context, queues []string) (string, string, error) {
+	timeout := time.NewTimer(POP_TIMEOUT)
+	defer timeout.Stop()
+	for {
+		m.mu.Lock()
+		for _, name := range queues {
+			q := m.queue(name)
+			best, bestScore := "", 0.0
+			for id, score := range q.pending {
+				if best == "" || score < bestScore || (score == bestScore && id < best) {
+					best, bestScore = id, score
+				}
+			}
+			if best != "" {
+				delete(q.pending, best)
+				q.processing = append(q.processing, best)
+				m.mu.Unlock()
+				return best, queueName(name), nil
+			}
+		}
+		wake := m.wake
+		m.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		case <-timeout.C:
+			return "", "", ErrQueueTimeout
+		case <-wake:
+		}
+	}
+}
+
+// AckProcessing removes a processed jobId from the processing list.
+func (m *MemoryQueue) AckProcessing(ctx context.Context, queue, jobId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q := m.queue(queue)
+	q.processing, _ = removeOne(q.processing, jobId)
+	delete(m.priorities, jobId)
+	return nil
+}
+
+// DropDuplicate removes one processing entry of a job that is already running elsewhere.
+func (m *MemoryQueue) DropDuplicate(ctx context.Context, queue, jobId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q := m.queue(queue)
+	q.processing, _ = removeOne(q.processing, jobId)
+	return nil
+}
+
+// RequeueFromProcessing moves a job back to pending with the priority it was pushed with.
+func (m *MemoryQueue) RequeueFromProcessing(ctx context.Context, queue, jobId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q := m.queue(queue)
+	q.processing, _ = removeOne(q.processing, jobId)
+	if _, ok := q.pending[jobId]; !ok {
+		q.pending[jobId] = pendingScore(time.Now(), m.priorities[jobId])
+	}
+	m.notify()
+	return nil
+}
+
+// RemovePending drops a job from the pending and delayed sets, e.g. after it was cancelled.
+func (m *MemoryQueue) RemovePending(ctx context.Context, queue, jobId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q := m.queue(queue)
+	delete(q.pending, jobId)
+	delete(q.delayed, jobId)
+	delete(m.priorities, jobId)
+	return nil
+}
+
+// Queues returns the names of all queues jobs have been pushed to, plus DEFAULT_QUEUE.
+func (m *MemoryQueue) Queues(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	queues := []string{DEFAULT_QUEUE}
+	for name := range m.queues {
+		if name != DEFAULT_QUEUE {
+			queues = append(queues, name)
+		}
+	}
+	sort.Strings(queues)
+	return queues, nil
+}
+
+// Deliveries returns 0: entries do not count their deliveries.
+func (m *MemoryQueue) Deliveries(ctx context.Context, queue, jobId string) (int64, error) {
+	return 0, nil
+}
+
+// DelayFromProcessing moves jobId from processing to the delayed set until the given time.
+func (m *MemoryQueue) DelayFromProcessing(ctx context.Context, queue, jobId string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q := m.queue(queue)
+	q.processing, _ = removeOne(q.processing, jobId)
+	q.delayed[jobId] = until
+	return nil
+}
+
+// PromoteDelayed moves up to PROMOTE_BATCH delayed jobs of queue that are due back to pending.
+func (m *MemoryQueue) PromoteDelayed(ctx context.Context, queue string, now time.Time, epoch int64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.leaderEpoch > epoch {
+		return 0, fmt.Errorf("%w: epoch %d is older than %d", ErrStaleLeader, epoch, m.leaderEpoch)
+	}
+	m.leaderEpoch = epoch
+	q := m.queue(queue)
+	n := 0
+	for id, until := range q.delayed {
+		if n == PROMOTE_BATCH {
+			break
+		}
+		if until.After(now) {
+			continue
+		}
+		delete(q.delayed, id)
+		if _, ok := q.pending[id]; !ok {
+			q.pending[id] = pendingScore(now, m.priorities[id])
+		}
+		n++
+	}
+	if n > 0 {
+		m.notify()
+	}
+	return n, nil
+}
+
+// MoveToDLQ moves a job from processing to the DLQ of queue.
+func (m *MemoryQueue) MoveToDLQ(ctx context.Context, queue, jobId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q := m.queue(queue)
+	q.processing, _ = removeOne(q.processing, jobId)
+	delete(m.priorities, jobId)
+	q.dead = append(q.dead, jobId)
+	return nil
+}
+
+// DeadLetters returns the IDs in the DLQ of queue, oldest first.
+func (m *MemoryQueue) DeadLetters(ctx context.Context, queue string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.queue(queue).dead), nil
+}
+
+// IsDeadLetter reports whether jobId is in the DLQ of queue.
+func (m *MemoryQueue) IsDeadLetter(ctx context.Context, queue, jobId string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Contains(m.queue(queue).dead, jobId), nil
+}
+
+// ReplayDeadLetter moves jobId from the DLQ of queue back to its pending set. It returns
+// false if the job was not dead-lettered.
+func (m *MemoryQueue) ReplayDeadLetter(ctx context.Context, queue, jobId string, priority int32) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q := m.queue(queue)
+	var ok bool
+	if q.dead, ok = removeOne(q.dead, jobId); !ok {
+		return false, nil
+	}
+	m.priorities[jobId] = priority
+	if _, ok := q.pending[jobId]; !ok {
+		q.pending[jobId] = pendingScore(time.Now(), priority)
+	}
+	m.notify()
+	return true, nil
+}
+
+// RemoveDeadLetter drops jobId from the DLQ of queue. It returns false if it was not there.
+func (m *MemoryQueue) RemoveDeadLetter(ctx context.Context, queue, jobId string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q := m.queue(queue)
+	n := len(q.dead)
+	q.dead = slices.DeleteFunc(q.dead, func(id string) bool { return id == jobId })
+	return len(q.dead) < n, nil
+}
+
+// RenewLease records that a worker is still processing jobId for another LEASE_TTL.
+func (m *MemoryQueue) RenewLease(ctx context.Context, jobId, workerId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.leases[jobId] = time.Now().Add(LEASE_TTL)
+	return nil
+}
+
+// ReleaseLease removes the lease for jobId once its worker is done with it.
+func (m *MemoryQueue) ReleaseLease(ctx context.Context, jobId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.leases, jobId)
+	return nil
+}
+
+// OrphanedJobs returns processing entries that no worker holds a live lease for.
+func (m *MemoryQueue) OrphanedJobs(ctx context.Context) ([]JobRef, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var orphans []JobRef
+	for name, q := range m.queues {
+		for _, id := range q.processing {
+			if expiry, ok := m.leases[id]; !ok || !expiry.After(now) {
+				orphans = append(orphans, JobRef{Queue: name, ID: id})
+			}
+		}
+	}
+	return orphans, nil
+}
+
+// RegisterWorker records info and renews the worker's lease for WORKER_LEASE_TTL.
+func (m *MemoryQueue) RegisterWorker(ctx context.Context, info WorkerInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	info.LastSeen = time.Now()
+	info.Running = slices.Clone(info.Running)
+	m.workers[info.ID] = &info
+	m.workerTTL[info.ID] = info.LastSeen.Add(WORKER_LEASE_TTL)
+	return nil
+}
+
+// DeregisterWorker removes a worker that is shutting down, along with its control.
+func (m *MemoryQueue) DeregisterWorker(ctx context.Context, workerId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.workers, workerId)
+	delete(m.workerTTL, workerId)
+	delete(m.controls, workerId)
+	return nil
+}
+
+// SetWorkerControl asks a worker to take the run state control.
+func (m *MemoryQueue) SetWorkerControl(ctx context.Context, workerId, control string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.controls[workerId] = control
+	return nil
+}
+
+// WorkerControl returns the run state the worker was asked to take, or "" if none.
+func (m *MemoryQueue) WorkerControl(ctx context.Context, workerId string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.controls[workerId], nil
+}
+
+// Workers lists the registered workers, with Alive unset for those whose lease expired.
+// Dead workers past WORKER_RETENTION are dropped from the registry.
+func (m *MemoryQueue) Workers(ctx context.Context) ([]*WorkerInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var workers []*WorkerInfo
+	for id, w := range m.workers {
+		info := *w
+		info.Alive = m.workerTTL[id].After(now)
+		if !info.Alive && info.expired(now) {
+			delete(m.workers, id)
+			delete(m.workerTTL, id)
+			delete(m.controls, id)
+			continue
+		}
+		workers = append(workers, &info)
+	}
+	sortWorkers(workers)
+	return workers, nil
+}
+
+// PublishCancel asks whichever worker is running jobId to stop it.
+func (m *MemoryQueue) PublishCancel(ctx context.Context, jobId string) error {
+	m.mu.Lock()
+	subs := slices.Clone(m.cancels)
+	m.mu.Unlock()
+	for _, ch := range subs {
+		select {
+		case ch <- jobId:
+		default: // a subscriber that is not keeping up misses it, like Redis pub/sub
+		}
+	}
+	return nil
+}
+
+// SubscribeCancellations delivers job IDs passed to PublishCancel until ctx is done.
+func (m *MemoryQueue) SubscribeCancellations(ctx context.Context) (<-chan string, error) {
+	ch := make(chan string, 16)
+	m.mu.Lock()
+	m.cancels = append(m.cancels, ch)
+	m.mu.Unlock()
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		defer func() {
+			m.mu.Lock()
+			m.cancels = slices.DeleteFunc(m.cancels, func(c chan string) bool { return c == ch })
+			m.mu.Unlock()
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case id := <-ch:
+				select {
+				case out <- id:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (m *MemoryQueue) appendJobEvent(jobId string, ev JobEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.eventSeq++
+	ev.ID = strconv.FormatInt(m.eventSeq, 10)
+	ev.Time = time.Now()
+	events := append(m.events[jobId], ev)
+	if len(events) > JOB_EVENTS_MAXLEN {
+		events = events[len(events)-JOB_EVENTS_MAXLEN:]
+	}
+	m.events[jobId] = events
+	m.notify()
+}
+
+// AppendJobOutput adds a chunk of a running job's stdout or stderr to its events.
+func (m *MemoryQueue) AppendJobOutput(ctx context.Context, jobId, stream string, data []byte) error {
+	m.appendJobEvent(jobId, JobEvent{Kind: stream, Data: string(data)})
+	return nil
+}
+
+// PublishJobStatus records a status change in the job's events.
+func (m *MemoryQueue) PublishJobStatus(ctx context.Context, jobId, status string, final bool) error {
+	m.appendJobEvent(jobId, JobEvent{Kind: EVENT_STATUS, Data: status, Final: final})
+	return nil
+}
+
+// LastJobEventID returns the ID of the job's newest event, or "0" if it has none.
+func (m *MemoryQueue) LastJobEventID(ctx context.Context, jobId string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	events := m.events[jobId]
+	if len(events) == 0 {
+		return "0", nil
+	}
+	return events[len(events)-1].ID, nil
+}
+
+// ReadJobEvents returns up to JOB_EVENTS_BATCH events after the given ID ("0" for the start).
+// With block > 0 it waits up to that long for new events and returns none on timeout.
+func (m *MemoryQueue) ReadJobEvents(ctx context.Context, jobId, after string, block time.Duration) ([]JobEvent, error) {
+	afterID, err := strconv.ParseInt(after, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job event ID %q", after)
+	}
+	deadline := time.NewTimer(block)
+	defer deadline.Stop()
+	for {
+		m.mu.Lock()
+		var events []JobEvent
+		for _, ev := range m.events[jobId] {
+			if id, _ := strconv.ParseInt(ev.ID, 10, 64); id > afterID {
+				events = append(events, ev)
+				if len(events) == JOB_EVENTS_BATCH {
+					break
+				}
+			}
+		}
+		wake := m.wake
+		m.mu.Unlock()
+		if len(events) > 0 || block <= 0 {
+			return events, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			return nil, nil
+		case <-wake:
+		}
+	}
+}
+
+// Close does nothing; the queues live as long as the MemoryQueue.
+func (m *MemoryQueue) Close() error {
+	return nil
+}
@@ -0,0 +1,225 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func pop(t *testing.T, m *MemoryQueue, queues ...string) (string, string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	id, queue, err := m.PopJob(ctx, queues)
+	if err != nil {
+		t.Fatalf("PopJob(%v): %v", queues, err)
+	}
+	return id, queue
+}
+
+func TestMemoryQueuePopsByPriorityAndQueueOrder(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryQueue()
+	m.PushJob(ctx, "", "low", 0)
+	m.PushJob(ctx, "", "high", 5)
+	m.PushJob(ctx, "batch", "other", 10)
+
+	if id, queue := pop(t, m, DEFAULT_QUEUE, "batch"); id != "high" || queue != DEFAULT_QUEUE {
+		t.Fatalf("popped %s from %s, want high from the default queue", id, queue)
+	}
+	if id, _ := pop(t, m, DEFAULT_QUEUE, "batch"); id != "low" {
+		t.Fatalf("popped %s, want low", id)
+	}
+	if id, queue := pop(t, m, DEFAULT_QUEUE, "batch"); id != "other" || queue != "batch" {
+		t.Fatalf("popped %s from %s, want other from batch", id, queue)
+	}
+}
+
+func TestMemoryQueuePopWaitsForPush(t *testing.T) {
+	m := NewMemoryQueue()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		m.PushJob(context.Background(), "", "late", 0)
+	}()
+	if id, _ := pop(t, m, DEFAULT_QUEUE); id != "late" {
+		t.Fatalf("popped %s, want late", id)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := m.PopJob(ctx, []string{DEFAULT_QUEUE}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("PopJob on a done context = %v", err)
+	}
+}
+
+func TestMemoryQueueDelayAndPromote(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryQueue()
+	m.PushJob(ctx, "", "a", 0)
+	pop(t, m, DEFAULT_QUEUE)
+
+	retryAt := time.Now().Add(time.Minute)
+	if err := m.DelayFromProcessing(ctx, "", "a", retryAt); err != nil {
+		t.Fatalf("DelayFromProcessing: %v", err)
+	}
+	if n, err := m.PromoteDelayed(ctx, "", time.Now(), 1); err != nil || n != 0 {
+		t.Fatalf("PromoteDelayed before the backoff expired = %d, %v; want 0", n, err)
+	}
+	if n, err := m.PromoteDelayed(ctx, "", retryAt, 1); err != nil || n != 1 {
+		t.Fatalf("PromoteDelayed once due = %d, %v; want 1", n, err)
+	}
+	if id, _ := pop(t, m, DEFAULT_QUEUE); id != "a" {
+		t.Fatalf("popped %s, want the promoted job", id)
+	}
+}
+
+func TestMemoryQueuePromoteDelayedIsFenced(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryQueue()
+	if _, err := m.PromoteDelayed(ctx, "", time.Now(), 2); err != nil {
+		t.Fatalf("PromoteDelayed(epoch 2): %v", err)
+	}
+	if _, err := m.PromoteDelayed(ctx, "", time.Now(), 2); err != nil {
+		t.Fatalf("PromoteDelayed(epoch 2) again: %v", err)
+	}
+	if _, err := m.PromoteDelayed(ctx, "", time.Now(), 1); !errors.Is(err, ErrStaleLeader) {
+		t.Fatalf("PromoteDelayed(epoch 1) = %v, want ErrStaleLeader", err)
+	}
+}
+
+func TestMemoryQueueDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryQueue()
+	m.PushJob(ctx, "", "a", 3)
+	pop(t, m, DEFAULT_QUEUE)
+	if err := m.MoveToDLQ(ctx, "", "a"); err != nil {
+		t.Fatalf("MoveToDLQ: %v", err)
+	}
+	if dead, _ := m.IsDeadLetter(ctx, "", "a"); !dead {
+		t.Fatal("job not in the DLQ")
+	}
+	if ids, _ := m.DeadLetters(ctx, ""); !slices.Equal(ids, []string{"a"}) {
+		t.Fatalf("DeadLetters = %v", ids)
+	}
+
+	if moved, err := m.ReplayDeadLetter(ctx, "", "a", 3); err != nil || !moved {
+		t.Fatalf("ReplayDeadLetter = %v, %v; want true", moved, err)
+	}
+	if moved, _ := m.ReplayDeadLetter(ctx, "", "a", 3); moved {
+		t.Fatal("replayed a job that is no longer dead-lettered")
+	}
+	if id, _ := pop(t, m, DEFAULT_QUEUE); id != "a" {
+		t.Fatalf("popped %s, want the replayed job", id)
+	}
+}
+
+func TestMemoryQueueOrphanedJobs(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryQueue()
+	m.PushJob(ctx, "", "leased", 0)
+	m.PushJob(ctx, "", "orphan", 0)
+	pop(t, m, DEFAULT_QUEUE)
+	pop(t, m, DEFAULT_QUEUE)
+	m.RenewLease(ctx, "leased", "w1")
+
+	refs, err := m.OrphanedJobs(ctx)
+	if err != nil || len(refs) != 1 || refs[0] != (JobRef{Queue: DEFAULT_QUEUE, ID: "orphan"}) {
+		t.Fatalf("OrphanedJobs = %v, %v; want only orphan", refs, err)
+	}
+	m.ReleaseLease(ctx, "leased")
+	if refs, _ := m.OrphanedJobs(ctx); len(refs) != 2 {
+		t.Fatalf("OrphanedJobs after release = %v, want both", refs)
+	}
+}
+
+func TestMemoryQueueWorkerControl(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryQueue()
+	if err := m.RegisterWorker(ctx, WorkerInfo{ID: "w1", State: WORKER_RUNNING}); err != nil {
+		t.Fatalf("RegisterWorker: %v", err)
+	}
+	if control, _ := m.WorkerControl(ctx, "w1"); control != "" {
+		t.Fatalf("control before a request = %q", control)
+	}
+	m.SetWorkerControl(ctx, "w1", WORKER_DRAINING)
+	if control, _ := m.WorkerControl(ctx, "w1"); control != WORKER_DRAINING {
+		t.Fatalf("control = %q, want %s", control, WORKER_DRAINING)
+	}
+	workers, _ := m.Workers(ctx)
+	if len(workers) != 1 || !workers[0].Alive {
+		t.Fatalf("Workers = %+v, want w1 alive", workers)
+	}
+
+	// A drained worker leaves the registry with its control
+	m.DeregisterWorker(ctx, "w1")
+	if workers, _ := m.Workers(ctx); len(workers) != 0 {
+		t.Fatalf("Workers after deregistering = %+v", workers)
+	}
+	if control, _ := m.WorkerControl(ctx, "w1"); control != "" {
+		t.Fatalf("control survived deregistration: %q", control)
+	}
+}
+
+func TestMemoryQueueJobEvents(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryQueue()
+	if last, _ := m.LastJobEventID(ctx, "a"); last != "0" {
+		t.Fatalf("LastJobEventID without events = %q", last)
+	}
+	m.PublishJobStatus(ctx, "a", "RUNNING", false)
+	m.AppendJobOutput(ctx, "a", EVENT_STDOUT, []byte("hello"))
+	m.PublishJobStatus(ctx, "a", "SUCCEEDED", true)
+
+	events, err := m.ReadJobEvents(ctx, "a", "0", 0)
+	if err != nil || len(events) != 3 {
+		t.Fatalf("ReadJobEvents = %+v, %v; want 3 events", events, err)
+	}
+	if events[1].Kind != EVENT_STDOUT || events[1].Data != "hello" || !events[2].Final {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	rest, _ := m.ReadJobEvents(ctx, "a", events[1].ID, 0)
+	if len(rest) != 1 || rest[0].Data != "SUCCEEDED" {
+		t.Fatalf("events after %s = %+v", events[1].ID, rest)
+	}
+	if none, _ := m.ReadJobEvents(ctx, "a", events[2].ID, 20*time.Millisecond); len(none) != 0 {
+		t.Fatalf("blocking read returned %+v", none)
+	}
+}
+
+func TestMemoryQueueCancellations(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewMemoryQueue()
+	ch, err := m.SubscribeCancellations(ctx)
+	if err != nil {
+		t.Fatalf("SubscribeCancellations: %v", err)
+	}
+	m.PublishCancel(ctx, "a")
+	select {
+	case id := <-ch:
+		if id != "a" {
+			t.Fatalf("received cancellation of %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancellation not delivered")
+	}
+}
+
+func TestMemoryQueuePushKeepsDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryQueue()
+	m.PushJob(ctx, "", "cron", 0)
+	pop(t, m, DEFAULT_QUEUE)
+	m.MoveToDLQ(ctx, "", "cron")
+
+	// The next cron run is queued while the failed one stays dead-lettered
+	m.PushJob(ctx, "", "cron", 0)
+	if dead, _ := m.IsDeadLetter(ctx, "", "cron"); !dead {
+		t.Fatal("push took the job out of the DLQ")
+	}
+	if id, _ := pop(t, m, DEFAULT_QUEUE); id != "cron" {
+		t.Fatalf("popped %s, want the pushed job", id)
+	}
+}
@@ -0,0 +1,356 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// PG_POP_POLL_INTERVAL is how often PopJob looks for pending rows in Postgres.
+	PG_POP_POLL_INTERVAL = time.Second
+	// PG_EVENTS_POLL_INTERVAL is how often a blocking ReadJobEvents looks for new rows.
+	PG_EVENTS_POLL_INTERVAL = 500 * time.Millisecond
+	// PG_EVENTS_TRIM_EVERY is how many event appends pass between trims of job_events.
+	PG_EVENTS_TRIM_EVERY = 1000
+)
+
+// Queue states of a row in tasks; NULL means the task is in no queue.
+const (
+	pgPending    = "pending"
+	pgProcessing = "processing"
+	pgDelayed    = "delayed"
+)
+
+// PostgresQueue keeps the queues in the tasks table, so a deployment needs no Redis. Each
+// task row carries its queue state, and workers claim pending rows with FOR UPDATE SKIP
+// LOCKED. DLQ membership is a separate dead_since column, so a dead-lettered task can be
+// queued again (e.g. for its next cron run) without leaving its DLQ, as in Redis. Cancellations use LISTEN/NOTIFY and job events the job_events table.
+type PostgresQueue struct {
+	pool    *pgxpool.Pool
+	appends atomic.Int64 // event appends, paces trimming of job_events
+}
+
+// NewPostgresQueue connects to the database at dsn. The queue columns of tasks and the
+// job_events table come from the db migrations (see db.Migrator).
+func NewPostgresQueue(dsn string) (*PostgresQueue, error) {
+	pool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Using Postgres queue backend")
+	return &PostgresQueue{pool: pool}, nil
+}
+
+// PushJob makes jobId pending. Like the Redis backend, a job that is already pending keeps its
+// position; one that is being processed is left alone, so a repeated push cannot run it twice.
+// A dead-lettered job stays in its DLQ.
+func (q *PostgresQueue) PushJob(ctx context.Context, queue, jobId string, priority int32) error {
+	_, err := q.pool.Exec(ctx,
+		`UPDATE tasks SET queue_state=$2, queue_since=now(), delayed_until=NULL
+		 WHERE id=$1 AND queue_state IS DISTINCT FROM $2 AND queue_state IS DISTINCT FROM $3`,
+		jobId, pgPending, pgProcessing)
+	return err
+}
+
+// PopJob claims the best pending row of the first non-empty queue in queues, ordered like the
+// Redis pending set, polling every PG_POP_POLL_INTERVAL until POP_TIMEOUT.
+func (q *PostgresQueue) PopJob(ctx context.Context, queues []string) (string, string, error) {
+	names := make([]string, len(queues))
+	for i, name := range queues {
+		names[i] = queueName(name)
+	}
+	deadline := time.Now().Add(POP_TIMEOUT)
+	for {
+		var id, queue string
+		err := q.pool.QueryRow(ctx,
+			`UPDATE tasks t SET queue_state=$2, queue_since=now()
+			 FROM (
+			   SELECT id FROM tasks
+			   WHERE queue_state=$3 AND queue = ANY($1::text[])
+			   ORDER BY array_position($1::text[], queue), queue_since - priority * $4 * interval '1 millisecond', id
+			   LIMIT 1
+			   FOR UPDATE SKIP LOCKED
+			 ) next
+			 WHERE t.id = next.id
+			 RETURNING t.id, t.queue`,
+			names, pgProcessing, pgPending, PRIORITY_AGING_STEP.Milliseconds()).Scan(&id, &queue)
+		if err == nil {
+			log.Printf("PopJob: moved job %s to processing queue of %s", id, queue)
+			return id, queue, nil
+		}
+		if !isNoRows(err) {
+			log.Printf("PopJob: claim error: %v", err)
+			return "", "", err
+		}
+		if time.Now().After(deadline) {
+			return "", "", ErrQueueTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		case <-time.After(PG_POP_POLL_INTERVAL):
+		}
+	}
+}
+
+func isNoRows(err error) bool { return errors.Is(err, pgx.ErrNoRows) }
+
+// setState moves jobId from one of the states in from to state and reports whether it did.
+func (q *PostgresQueue) setState(ctx context.Context, jobId string, state any, from ...string) (bool, error) {
+	cmdTag, err := q.pool.Exec(ctx,
+		`UPDATE tasks SET queue_state=$2, queue_since=now() WHERE id=$1 AND queue_state = ANY($3)`,
+		jobId, state, from)
+	if err != nil {
+		return false, err
+	}
+	return cmdTag.RowsAffected() > 0, nil
+}
+
+func (q *PostgresQueue) AckProcessing(ctx context.Context, queue, jobId string) error {
+	_, err := q.setState(ctx, jobId, nil, pgProcessing)
+	return err
+}
+
+// DropDuplicate does nothing: a row is processed at most once at a time, so there is no
+// second entry to drop.
+func (q *PostgresQueue) DropDuplicate(ctx context.Context, queue, jobId string) error {
+	return nil
+}
+
+// Deliveries returns 0: rows do not count their deliveries.
+func (q *PostgresQueue) Deliveries(ctx context.Context, queue, jobId string) (int64, error) {
+	return 0, nil
+}
+
+func (q *PostgresQueue) RequeueFromProcessing(ctx context.Context, queue, jobId string) error {
+	_, err := q.setState(ctx, jobId, pgPending, pgProcessing)
+	return err
+}
+
+func (q *PostgresQueue) RemovePending(ctx context.Context, queue, jobId string) error {
+	_, err := q.setState(ctx, jobId, nil, pgPending, pgDelayed)
+	return err
+}
+
+// Queues returns the queues that have jobs in any state, plus DEFAULT_QUEUE.
+func (q *PostgresQueue) Queues(ctx context.Context) ([]string, error) {
+	rows, err := q.pool.Query(ctx, `SELECT DISTINCT queue FROM tasks WHERE queue_state IS NOT NULL OR dead_since IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var queues []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		queues = append(queues, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(queues, DEFAULT_QUEUE) {
+		queues = append(queues, DEFAULT_QUEUE)
+	}
+	sort.Strings(queues)
+	return queues, nil
+}
+
+func (q *PostgresQueue) DelayFromProcessing(ctx context.Context, queue, jobId string, until time.Time) error {
+	_, err := q.pool.Exec(ctx,
+		`UPDATE tasks SET queue_state=$2, queue_since=now(), delayed_until=$3 WHERE id=$1 AND queue_state=$4`,
+		jobId, pgDelayed, until, pgProcessing)
+	return err
+}
+
+// PromoteDelayed moves due delayed rows of queue back to pending. It is fenced by the
+// leader_fence row like the leader writes of db.DBManager.
+func (q *PostgresQueue) PromoteDelayed(ctx context.Context, queue string, now time.Time, epoch int64) (int, error) {
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	var current int64
+	if err := tx.QueryRow(ctx,
+		`SELECT COALESCE((SELECT epoch FROM leader_fence WHERE id = 1 FOR SHARE), 0)`).Scan(&current); err != nil {
+		return 0, err
+	}
+	if current > epoch {
+		return 0, fmt.Errorf("%w: epoch %d is older than %d", ErrStaleLeader, epoch, current)
+	}
+
+	cmdTag, err := tx.Exec(ctx,
+		`UPDATE tasks SET queue_state=$3, queue_since=$2, delayed_until=NULL, leader_epoch=$6
+		 WHERE id IN (
+		   SELECT id FROM tasks WHERE queue_state=$4 AND queue=$1 AND delayed_until <= $2 AND leader_epoch <= $6
+		   ORDER BY delayed_until LIMIT $5
+		   FOR UPDATE SKIP LOCKED
+		 )`,
+		queueName(queue), now, pgPending, pgDelayed, PROMOTE_BATCH, epoch)
+	if err != nil {
+		return 0, err
+	}
+	return int(cmdTag.RowsAffected()), tx.Commit(ctx)
+}
+
+func (q *PostgresQueue) MoveToDLQ(ctx context.Context, queue, jobId string) error {
+	_, err := q.pool.Exec(ctx,
+		`UPDATE tasks SET queue_state=NULL, queue_since=now(), dead_since=now() WHERE id=$1 AND queue_state=$2`,
+		jobId, pgProcessing)
+	return err
+}
+
+// DeadLetters returns the IDs in the DLQ of queue, oldest first.
+func (q *PostgresQueue) DeadLetters(ctx context.Context, queue string) ([]string, error) {
+	rows, err := q.pool.Query(ctx,
+		`SELECT id FROM tasks WHERE dead_since IS NOT NULL AND queue=$1 ORDER BY dead_since, id`, queueName(queue))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (q *PostgresQueue) IsDeadLetter(ctx context.Context, queue, jobId string) (bool, error) {
+	var dead bool
+	err := q.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM tasks WHERE id=$1 AND queue=$2 AND dead_since IS NOT NULL)`, jobId, queueName(queue)).Scan(&dead)
+	return dead, err
+}
+
+// ReplayDeadLetter takes jobId out of its DLQ and makes it pending, unless it already is
+// pending or being processed.
+func (q *PostgresQueue) ReplayDeadLetter(ctx context.Context, queue, jobId string, priority int32) (bool, error) {
+	cmdTag, err := q.pool.Exec(ctx,
+		`UPDATE tasks SET dead_since=NULL,
+		   queue_since=CASE WHEN queue_state IN ($2, $3) THEN queue_since ELSE now() END,
+		   delayed_until=CASE WHEN queue_state IN ($2, $3) THEN delayed_until END,
+		   queue_state=CASE WHEN queue_state IN ($2, $3) THEN queue_state ELSE $2 END
+		 WHERE id=$1 AND dead_since IS NOT NULL`,
+		jobId, pgPending, pgProcessing)
+	if err != nil {
+		return false, err
+	}
+	return cmdTag.RowsAffected() > 0, nil
+}
+
+func (q *PostgresQueue) RemoveDeadLetter(ctx context.Context, queue, jobId string) (bool, error) {
+	cmdTag, err := q.pool.Exec(ctx, `UPDATE tasks SET dead_since=NULL WHERE id=$1 AND dead_since IS NOT NULL`, jobId)
+	if err != nil {
+		return false, err
+	}
+	return cmdTag.RowsAffected() > 0, nil
+}
+
+// RenewLease records that workerId is still processing jobId, until LEASE_TTL from now.
+func (q *PostgresQueue) RenewLease(ctx context.Context, jobId, workerId string) error {
+	_, err := q.pool.Exec(ctx,
+		`UPDATE tasks SET lease_owner=$2, lease_expires_at=now() + $3 * interval '1 millisecond' WHERE id=$1`,
+		jobId, workerId, LEASE_TTL.Milliseconds())
+	return err
+}
+
+func (q *PostgresQueue) ReleaseLease(ctx context.Context, jobId string) error {
+	_, err := q.pool.Exec(ctx, `UPDATE tasks SET lease_owner=NULL, lease_expires_at=NULL WHERE id=$1`, jobId)
+	return err
+}
+
+// OrphanedJobs returns processing rows whose lease is missing or expired.
+func (q *PostgresQueue) OrphanedJobs(ctx context.Context) ([]JobRef, error) {
+	rows, err := q.pool.Query(ctx,
+		`SELECT queue, id FROM tasks WHERE queue_state=$1 AND (lease_expires_at IS NULL OR lease_expires_at < now())`, pgProcessing)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var orphans []JobRef
+	for rows.Next() {
+		var ref JobRef
+		if err := rows.Scan(&ref.Queue, &ref.ID); err != nil {
+			return nil, err
+		}
+		orphans = append(orphans, ref)
+	}
+	return orphans, rows.Err()
+}
+
+// PublishCancel notifies CANCEL_CHANNEL listeners, i.e. every worker, about jobId.
+func (q *PostgresQueue) PublishCancel(ctx context.Context, jobId string) error {
+	_, err := q.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, CANCEL_CHANNEL, jobId)
+	return err
+}
+
+// SubscribeCancellations delivers job IDs passed to PublishCancel until ctx is done. It holds
+// one pooled connection for LISTEN and re-listens after connection errors.
+func (q *PostgresQueue) SubscribeCancellations(ctx context.Context) (<-chan string, error) {
+	conn, err := q.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Listen before returning so no cancellation published after this call is missed
+	if _, err := conn.Exec(ctx, "LISTEN "+CANCEL_CHANNEL); err != nil {
+		conn.Release()
+		return nil, err
+	}
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for {
+			n, err := conn.Conn().WaitForNotification(ctx)
+			if err == nil {
+				select {
+				case out <- n.Payload:
+				case <-ctx.Done():
+					conn.Release()
+					return
+				}
+				continue
+			}
+			conn.Release()
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Cancellation listener lost its connection, reconnecting: %v", err)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(RECONNECT_DELAY):
+				}
+				if conn, err = q.pool.Acquire(ctx); err != nil {
+					continue
+				}
+				if _, err = conn.Exec(ctx, "LISTEN "+CANCEL_CHANNEL); err != nil {
+					conn.Release()
+					continue
+				}
+				break
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (q *PostgresQueue) Close() error {
+	q.pool.Close()
+	return nil
+}
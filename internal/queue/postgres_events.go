@@ -0,0 +1,91 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func (q *PostgresQueue) appendJobEvent(ctx context.Context, jobId, kind string, data []byte, final bool) error {
+	if _, err := q.pool.Exec(ctx,
+		`INSERT INTO job_events (task_id, kind, data, final) VALUES ($1, $2, $3, $4)`, jobId, kind, data, final); err != nil {
+		return err
+	}
+	if q.appends.Add(1)%PG_EVENTS_TRIM_EVERY == 0 {
+		q.trimJobEvents(ctx, jobId)
+	}
+	return nil
+}
+
+// trimJobEvents drops events older than JOB_EVENTS_TTL and caps jobId's events at
+// JOB_EVENTS_MAXLEN. Errors only delay the cleanup, so they are not returned.
+func (q *PostgresQueue) trimJobEvents(ctx context.Context, jobId string) {
+	_, _ = q.pool.Exec(ctx,
+		`DELETE FROM job_events WHERE created_at < now() - $1 * interval '1 millisecond'`, JOB_EVENTS_TTL.Milliseconds())
+	_, _ = q.pool.Exec(ctx,
+		`DELETE FROM job_events WHERE task_id=$1 AND id <= (
+		   SELECT id FROM job_events WHERE task_id=$1 ORDER BY id DESC OFFSET $2 LIMIT 1
+		 )`, jobId, JOB_EVENTS_MAXLEN)
+}
+
+func (q *PostgresQueue) AppendJobOutput(ctx context.Context, jobId, stream string, data []byte) error {
+	return q.appendJobEvent(ctx, jobId, stream, data, false)
+}
+
+func (q *PostgresQueue) PublishJobStatus(ctx context.Context, jobId, status string, final bool) error {
+	return q.appendJobEvent(ctx, jobId, EVENT_STATUS, []byte(status), final)
+}
+
+func (q *PostgresQueue) LastJobEventID(ctx context.Context, jobId string) (string, error) {
+	var id int64
+	err := q.pool.QueryRow(ctx, `SELECT COALESCE(max(id), 0) FROM job_events WHERE task_id=$1`, jobId).Scan(&id)
+	if err != nil {
+		return "0", err
+	}
+	return strconv.FormatInt(id, 10), nil
+}
+
+// ReadJobEvents returns up to JOB_EVENTS_BATCH events after the given ID ("0" for the start).
+// With block > 0 it polls every PG_EVENTS_POLL_INTERVAL until events arrive or block passes.
+func (q *PostgresQueue) ReadJobEvents(ctx context.Context, jobId, after string, block time.Duration) ([]JobEvent, error) {
+	afterID, err := strconv.ParseInt(after, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job event ID %q", after)
+	}
+	deadline := time.Now().Add(block)
+	for {
+		events, err := q.readJobEvents(ctx, jobId, afterID)
+		if err != nil || len(events) > 0 || !time.Now().Before(deadline) {
+			return events, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(PG_EVENTS_POLL_INTERVAL):
+		}
+	}
+}
+
+func (q *PostgresQueue) readJobEvents(ctx context.Context, jobId string, afterID int64) ([]JobEvent, error) {
+	rows, err := q.pool.Query(ctx,
+		`SELECT id, kind, data, final, created_at FROM job_events WHERE task_id=$1 AND id > $2 ORDER BY id LIMIT $3`,
+		jobId, afterID, JOB_EVENTS_BATCH)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var events []JobEvent
+	for rows.Next() {
+		var id int64
+		var data []byte
+		ev := JobEvent{}
+		if err := rows.Scan(&id, &ev.Kind, &data, &ev.Final, &ev.Time); err != nil {
+			return nil, err
+		}
+		ev.ID = strconv.FormatInt(id, 10)
+		ev.Data = string(data)
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,80 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// RegisterWorker records info in the workers table and renews the worker's lease for
+// WORKER_LEASE_TTL, both by the database clock.
+func (q *PostgresQueue) RegisterWorker(ctx context.Context, info WorkerInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	_, err = q.pool.Exec(ctx,
+		`INSERT INTO workers (id, info, last_seen, lease_expires_at) VALUES ($1, $2, now(), now() + $3 * interval '1 millisecond')
+		 ON CONFLICT (id) DO UPDATE SET info=EXCLUDED.info, last_seen=EXCLUDED.last_seen, lease_expires_at=EXCLUDED.lease_expires_at`,
+		info.ID, data, WORKER_LEASE_TTL.Milliseconds())
+	return err
+}
+
+// DeregisterWorker removes a worker that is shutting down.
+func (q *PostgresQueue) DeregisterWorker(ctx context.Context, workerId string) error {
+	_, err := q.pool.Exec(ctx, `DELETE FROM workers WHERE id=$1`, workerId)
+	return err
+}
+
+// SetWorkerControl asks a worker to take the run state control, which it picks up on its
+// next poll of WorkerControl. Unregistered workers are ignored.
+func (q *PostgresQueue) SetWorkerControl(ctx context.Context, workerId, control string) error {
+	_, err := q.pool.Exec(ctx, `UPDATE workers SET control=$2 WHERE id=$1`, workerId, control)
+	return err
+}
+
+// WorkerControl returns the run state the worker was asked to take, or "" if none.
+func (q *PostgresQueue) WorkerControl(ctx context.Context, workerId string) (string, error) {
+	var control string
+	err := q.pool.QueryRow(ctx, `SELECT control FROM workers WHERE id=$1`, workerId).Scan(&control)
+	if isNoRows(err) {
+		return "", nil
+	}
+	return control, err
+}
+
+// Workers lists the registered workers, with Alive unset for those whose lease expired.
+// Dead workers past WORKER_RETENTION are dropped from the registry.
+func (q *PostgresQueue) Workers(ctx context.Context) ([]*WorkerInfo, error) {
+	if _, err := q.pool.Exec(ctx,
+		`DELETE FROM workers WHERE lease_expires_at < now() AND last_seen < now() - $1 * interval '1 millisecond'`,
+		WORKER_RETENTION.Milliseconds()); err != nil {
+		return nil, err
+	}
+	rows, err := q.pool.Query(ctx, `SELECT id, info, last_seen, lease_expires_at > now() FROM workers`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var workers []*WorkerInfo
+	for rows.Next() {
+		info := &WorkerInfo{}
+		var id string
+		var data []byte
+		var lastSeen time.Time
+		var alive bool
+		if err := rows.Scan(&id, &data, &lastSeen, &alive); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, info); err != nil {
+			return nil, err
+		}
+		info.ID, info.LastSeen, info.Alive = id, lastSeen, alive
+		workers = append(workers, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sortWorkers(workers)
+	return workers, nil
+}
@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PRIORITY_AGING_STEP is how much queueing time one priority point is worth. A job that
+// has waited longer than (p2-p1)*PRIORITY_AGING_STEP is popped before a newer job with
+// priority p2, so low-priority work keeps making progress under a steady high-priority load.
+const PRIORITY_AGING_STEP = 30 * time.Second
+
+// pendingScore orders the pending set: lower scores are popped first.
+func pendingScore(enqueuedAt time.Time, priority int32) float64 {
+	return float64(enqueuedAt.UnixMilli() - int64(priority)*PRIORITY_AGING_STEP.Milliseconds())
+}
+
+// popScript takes KEYS as (pending set, processing list) pairs and atomically moves the
+// lowest-scored job of the first non-empty pending set onto its processing list. It returns
+// the job ID and the 1-based index of the pair it came from.
+var popScript = redis.NewScript(`
+for i = 1, #KEYS, 2 do
+  local ids = redis.call('ZRANGE', KEYS[i], 0, 0)
+  if #ids > 0 then
+    redis.call('ZREM', KEYS[i], ids[1])
+    redis.call('LPUSH', KEYS[i + 1], ids[1])
+    return {ids[1], tostring((i + 1) / 2)}
+  end
+end
+return false
+`)
+
+// migrateScript moves job IDs from the pre-priority pending list into the pending set.
+var migrateScript = redis.NewScript(`
+if redis.call('TYPE', KEYS[1]).ok ~= 'list' then
+  return 0
+end
+local ids = redis.call('LRANGE', KEYS[1], 0, -1)
+for i, id in ipairs(ids) do
+  redis.call('ZADD', KEYS[2], 'NX', tonumber(ARGV[1]) + i, id)
+end
+redis.call('DEL', KEYS[1])
+return #ids
+`)
+
+// jobPriority returns the priority recorded for jobId when it was pushed (0 if unknown).
+func jobPriority(ctx context.Context, client *redis.Client, jobId string) int32 {
+	v, err := client.HGet(ctx, JOB_PRIORITIES_HASH, jobId).Result()
+	if err != nil {
+		return 0
+	}
+	p, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(p)
+}
+
+// migrateLegacyPending carries jobs queued by older versions, which kept a plain list under
+// LEGACY_PENDING_JOBS_QUEUE, over to the priority set with default priority.
+func (m *QueueManager) migrateLegacyPending(ctx context.Context) {
+	n, err := migrateScript.Run(ctx, m.client, []string{LEGACY_PENDING_JOBS_QUEUE, PENDING_JOBS_QUEUE}, time.Now().UnixMilli()).Int()
+	if err != nil {
+		log.Printf("Failed to migrate legacy pending queue: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("Migrated %d jobs from %s to %s", n, LEGACY_PENDING_JOBS_QUEUE, PENDING_JOBS_QUEUE)
+	}
+}
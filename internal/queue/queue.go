@@ -3,18 +3,36 @@ package queue
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
 const (
-	PENDING_JOBS_QUEUE    = "pending_jobs"
-	PROCESSING_JOBS_QUEUE = "processing_jobs"
-	DLQ_JOBS_QUEUE        = "dlq_tasks"
-	RECONNECT_DELAY       = 5 * time.Second
-	POP_TIMEOUT           = 5 * time.Second
+	// PENDING_JOBS_QUEUE is a sorted set scored by pendingScore.
+	PENDING_JOBS_QUEUE        = "pending_jobs_pq"
+	LEGACY_PENDING_JOBS_QUEUE = "pending_jobs"
+	JOB_PRIORITIES_HASH       = "job_priorities"
+	PROCESSING_JOBS_QUEUE     = "processing_jobs"
+	DLQ_JOBS_QUEUE            = "dlq_tasks"
+	DELAYED_JOBS_QUEUE        = "delayed_jobs"
+	QUEUES_SET                = "queues"
+	CANCEL_CHANNEL            = "cancel_jobs"
+	LEASE_KEY_PREFIX          = "job_lease:"
+	RECONNECT_DELAY           = 5 * time.Second
+	POP_TIMEOUT               = 5 * time.Second
+	POP_POLL_INTERVAL         = 250 * time.Millisecond
+	// LEASE_TTL is how long a job lease survives without a heartbeat from its worker.
+	LEASE_TTL = 30 * time.Second
+	// DEFAULT_QUEUE uses the unsuffixed key names above, so it matches older deployments.
+	DEFAULT_QUEUE = "default"
 )
 
 var (
@@ -23,7 +41,28 @@ var (
 	ErrJobAlreadyQueued  = errors.New("job already in queue")
 )
 
+// JobRef identifies a job entry in a named queue.
+type JobRef struct {
+	Queue string
+	ID    string
+}
+
+// queueKey derives the Redis key of a named queue from the key used by the default queue.
+func queueKey(base, queue string) string {
+	if queue == "" || queue == DEFAULT_QUEUE {
+		return base
+	}
+	return base + ":" + queue
+}
+
+func pendingKey(queue string) string    { return queueKey(PENDING_JOBS_QUEUE, queue) }
+func processingKey(queue string) string { return queueKey(PROCESSING_JOBS_QUEUE, queue) }
+func dlqKey(queue string) string        { return queueKey(DLQ_JOBS_QUEUE, queue) }
+func delayedKey(queue string) string    { return queueKey(DELAYED_JOBS_QUEUE, queue) }
+
+// QueueManager is safe for concurrent use; mu guards reconnects replacing client.
 type QueueManager struct {
+	mu     sync.Mutex
 	client *redis.Client
 	addr   string
 }
@@ -36,10 +75,12 @@ func NewQueueManager(addr string) (*QueueManager, error) {
 	if err := qm.connect(); err != nil {
 		return nil, err
 	}
+	qm.migrateLegacyPending(context.Background())
 
 	return qm, nil
 }
 
+// connect (re)creates the Redis client. Callers other than the constructor must hold mu.
 func (m *QueueManager) connect() error {
 	if m.client != nil {
 		m.client.Close()
@@ -62,30 +103,41 @@ func (m *QueueManager) connect() error {
 	return nil
 }
 
-func (m *QueueManager) ensureConnected(ctx context.Context) error {
+// ensureConnected returns a live client, reconnecting if the current one fails a ping.
+func (m *QueueManager) ensureConnected(ctx context.Context) (*redis.Client, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.client == nil {
-		return m.connect()
+		if err := m.connect(); err != nil {
+			return nil, err
+		}
+		return m.client, nil
 	}
 
 	// Test connection
 	if err := m.client.Ping(ctx).Err(); err != nil {
 		log.Printf("Redis connection lost, attempting to reconnect: %v", err)
-		return m.connect()
+		if err := m.connect(); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	return m.client, nil
 }
 
-func (m *QueueManager) PushJob(ctx context.Context, jobId string) error {
-	if err := m.ensureConnected(ctx); err != nil {
+// PushJob adds jobId to the pending set of queue. Higher priorities are popped first; pushing
+// a job that is already pending keeps its original position.
+func (m *QueueManager) PushJob(ctx context.Context, queue, jobId string, priority int32) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
 		log.Printf("Connection error in PushJob: %v", err)
 		return err
 	}
 
-	log.Printf("Attempting to push job %s to queue", jobId)
+	log.Printf("Attempting to push job %s to queue %s with priority %d", jobId, queue, priority)
 
 	// Test Redis connection with a simple ping
-	if err := m.client.Ping(ctx).Err(); err != nil {
+	if err := client.Ping(ctx).Err(); err != nil {
 		log.Printf("Redis ping failed in PushJob: %v", err)
 		return err
 	}
@@ -93,47 +145,78 @@ func (m *QueueManager) PushJob(ctx context.Context, jobId string) error {
 
 	// Try the push operation - background context to avoid cancellation issues
 	backgroundCtx := context.Background()
-	pushResult := m.client.RPush(backgroundCtx, PENDING_JOBS_QUEUE, jobId)
-	if err := pushResult.Err(); err != nil {
-		log.Printf("RPUSH failed for job %s: %v", jobId, err)
+	_, err = client.TxPipelined(backgroundCtx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(backgroundCtx, QUEUES_SET, queueName(queue))
+		pipe.HSet(backgroundCtx, JOB_PRIORITIES_HASH, jobId, priority)
+		pipe.ZAddNX(backgroundCtx, pendingKey(queue), redis.Z{Score: pendingScore(time.Now(), priority), Member: jobId})
+		return nil
+	})
+	if err != nil {
+		log.Printf("ZADD failed for job %s: %v", jobId, err)
 		return err
 	}
-	_, _ = pushResult.Result()
 	return nil
 }
 
-func (m *QueueManager) PopJob(ctx context.Context) (string, error) {
-	if err := m.ensureConnected(ctx); err != nil {
-		return "", err
+// PopJob moves the highest-priority pending job of the first non-empty queue in queues to
+// that queue's processing list and returns the job ID and queue name. Redis has no blocking
+// pop that also pushes onto a list, so the pending sets are polled until POP_TIMEOUT.
+func (m *QueueManager) PopJob(ctx context.Context, queues []string) (string, string, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return "", "", err
 	}
 
 	// Use background context for Redis operations to avoid context cancellation issues
 	backgroundCtx := context.Background()
 
+	keys := make([]string, 0, 2*len(queues))
+	for _, q := range queues {
+		keys = append(keys, pendingKey(q), processingKey(q))
+	}
+
 	// Atomically move from pending -> processing
-	log.Printf("PopJob: BRPOPLPUSH from %s to %s with timeout %v", PENDING_JOBS_QUEUE, PROCESSING_JOBS_QUEUE, POP_TIMEOUT)
-	jobId, err := m.client.BRPopLPush(backgroundCtx, PENDING_JOBS_QUEUE, PROCESSING_JOBS_QUEUE, POP_TIMEOUT).Result()
-	if err != nil {
-		if err == redis.Nil {
-			log.Printf("No jobs available in queue after %v timeout", POP_TIMEOUT)
-			return "", ErrQueueTimeout
+	log.Printf("PopJob: polling queues %v with timeout %v", queues, POP_TIMEOUT)
+	deadline := time.Now().Add(POP_TIMEOUT)
+	for {
+		res, err := popScript.Run(backgroundCtx, client, keys).StringSlice()
+		if err == nil && len(res) == 2 {
+			idx, _ := strconv.Atoi(res[1])
+			queue := queueName(queues[idx-1])
+			log.Printf("PopJob: moved job %s to processing queue of %s", res[0], queue)
+			return res[0], queue, nil
 		}
+		if err != redis.Nil {
+			log.Printf("PopJob: pop script error: %v", err)
+			// Try to reconnect on error, unless another caller already did
+			m.mu.Lock()
+			if m.client == client {
+				if err := m.connect(); err != nil {
+					m.mu.Unlock()
+					log.Printf("Failed to reconnect to Redis: %v", err)
+					return "", "", err
+				}
+			}
+			m.mu.Unlock()
 
-		log.Printf("PopJob: BRPOPLPUSH error: %v", err)
-		// Try to reconnect on error
-		if err := m.connect(); err != nil {
-			log.Printf("Failed to reconnect to Redis: %v", err)
-			return "", err
+			return "", "", err
 		}
 
-		return "", err
+		if time.Now().After(deadline) {
+			log.Printf("No jobs available in queue after %v timeout", POP_TIMEOUT)
+			return "", "", ErrQueueTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		case <-time.After(POP_POLL_INTERVAL):
+		}
 	}
-
-	log.Printf("PopJob: moved job %s to processing queue", jobId)
-	return jobId, nil
 }
 
 func (m *QueueManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.client != nil {
 		return m.client.Close()
 	}
@@ -141,32 +224,212 @@ func (m *QueueManager) Close() error {
 }
 
 // AckProcessing removes a processed jobId from the processing queue.
-func (m *QueueManager) AckProcessing(ctx context.Context, jobId string) error {
-	if err := m.ensureConnected(ctx); err != nil {
+func (m *QueueManager) AckProcessing(ctx context.Context, queue, jobId string) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
 		return err
 	}
-	_, err := m.client.LRem(ctx, PROCESSING_JOBS_QUEUE, 1, jobId).Result()
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, processingKey(queue), 1, jobId)
+		pipe.HDel(ctx, JOB_PRIORITIES_HASH, jobId)
+		return nil
+	})
 	return err
 }
 
-// RequeueFromProcessing moves a job back to pending and removes it from processing.
-func (m *QueueManager) RequeueFromProcessing(ctx context.Context, jobId string) error {
-	if err := m.ensureConnected(ctx); err != nil {
+// DropDuplicate removes one processing entry of a job that is already running elsewhere,
+// keeping its priority and the other entry.
+func (m *QueueManager) DropDuplicate(ctx context.Context, queue, jobId string) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
 		return err
 	}
-	if _, err := m.client.LRem(ctx, PROCESSING_JOBS_QUEUE, 1, jobId).Result(); err != nil {
+	return client.LRem(ctx, processingKey(queue), 1, jobId).Err()
+}
+
+// Deliveries returns 0: list entries do not count their deliveries.
+func (m *QueueManager) Deliveries(ctx context.Context, queue, jobId string) (int64, error) {
+	return 0, nil
+}
+
+// RequeueFromProcessing moves a job back to pending with the priority it was pushed with
+// and removes it from processing. It queues behind jobs of that priority already waiting.
+func (m *QueueManager) RequeueFromProcessing(ctx context.Context, queue, jobId string) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
 		return err
 	}
-	return m.client.RPush(ctx, PENDING_JOBS_QUEUE, jobId).Err()
+	priority := jobPriority(ctx, client, jobId)
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, processingKey(queue), 1, jobId)
+		pipe.ZAddNX(ctx, pendingKey(queue), redis.Z{Score: pendingScore(time.Now(), priority), Member: jobId})
+		return nil
+	})
+	return err
 }
 
 // MoveToDLQ moves a job to DLQ and removes it from processing.
-func (m *QueueManager) MoveToDLQ(ctx context.Context, jobId string) error {
-	if err := m.ensureConnected(ctx); err != nil {
+func (m *QueueManager) MoveToDLQ(ctx context.Context, queue, jobId string) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, processingKey(queue), 1, jobId)
+		pipe.HDel(ctx, JOB_PRIORITIES_HASH, jobId)
+		pipe.RPush(ctx, dlqKey(queue), jobId)
+		return nil
+	})
+	return err
+}
+
+// RemovePending drops a job from the pending and delayed sets, e.g. after it was cancelled.
+func (m *QueueManager) RemovePending(ctx context.Context, queue, jobId string) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
 		return err
 	}
-	if _, err := m.client.LRem(ctx, PROCESSING_JOBS_QUEUE, 1, jobId).Result(); err != nil {
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, pendingKey(queue), jobId)
+		pipe.ZRem(ctx, delayedKey(queue), jobId)
+		pipe.HDel(ctx, JOB_PRIORITIES_HASH, jobId)
+		return nil
+	})
+	return err
+}
+
+// PublishCancel asks whichever worker is running jobId to stop it.
+func (m *QueueManager) PublishCancel(ctx context.Context, jobId string) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
 		return err
 	}
-	return m.client.RPush(ctx, DLQ_JOBS_QUEUE, jobId).Err()
+	return client.Publish(ctx, CANCEL_CHANNEL, jobId).Err()
+}
+
+// SubscribeCancellations delivers job IDs passed to PublishCancel until ctx is done.
+func (m *QueueManager) SubscribeCancellations(ctx context.Context) (<-chan string, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub := client.Subscribe(ctx, CANCEL_CHANNEL)
+	// Wait for the subscription so no cancellation published after this call is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+		msgs := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				select {
+				case out <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// RenewLease records that workerId is still processing jobId. The lease expires after
+// LEASE_TTL unless renewed, which marks the processing entry as orphaned.
+func (m *QueueManager) RenewLease(ctx context.Context, jobId, workerId string) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return err
+	}
+	return client.Set(ctx, LEASE_KEY_PREFIX+jobId, workerId, LEASE_TTL).Err()
+}
+
+// ReleaseLease removes the lease for jobId once its worker is done with it.
+func (m *QueueManager) ReleaseLease(ctx context.Context, jobId string) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return err
+	}
+	return client.Del(ctx, LEASE_KEY_PREFIX+jobId).Err()
+}
+
+// Queues returns the names of all queues jobs have been pushed to.
+func (m *QueueManager) Queues(ctx context.Context) ([]string, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return nil, err
+	}
+	queues, err := client.SMembers(ctx, QUEUES_SET).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(queues, DEFAULT_QUEUE) {
+		queues = append(queues, DEFAULT_QUEUE)
+	}
+	sort.Strings(queues)
+	return queues, nil
+}
+
+// OrphanedJobs returns entries in any processing queue that no worker holds a lease for.
+func (m *QueueManager) OrphanedJobs(ctx context.Context) ([]JobRef, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return nil, err
+	}
+	queues, err := m.Queues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var orphans []JobRef
+	for _, queue := range queues {
+		ids, err := client.LRange(ctx, processingKey(queue), 0, -1).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = LEASE_KEY_PREFIX + id
+		}
+		holders, err := client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, err
+		}
+		for i, holder := range holders {
+			if holder == nil {
+				orphans = append(orphans, JobRef{Queue: queue, ID: ids[i]})
+			}
+		}
+	}
+	return orphans, nil
+}
+
+// validQueueName keeps queue names safe to embed in Redis keys.
+var validQueueName = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// ValidateQueueName reports whether name can be used as a queue name.
+func ValidateQueueName(name string) error {
+	if !validQueueName.MatchString(name) {
+		return fmt.Errorf("invalid queue name %q: use 1-64 letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
+
+// queueName maps the empty name to DEFAULT_QUEUE.
+func queueName(queue string) string {
+	if queue == "" {
+		return DEFAULT_QUEUE
+	}
+	return queue
 }
@@ -0,0 +1,403 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// JOB_STREAM is the stream of a queue; every entry carries one job ID in its "job" field.
+	JOB_STREAM = "job_stream"
+	// STREAM_GROUP is the consumer group all workers read JOB_STREAM through.
+	STREAM_GROUP = "workers"
+	// STREAM_ENTRIES_HASH maps a job ID to "<queue>|<entry ID>" of its current stream entry.
+	STREAM_ENTRIES_HASH = "job_stream_entries"
+	// STREAM_JOBS_SET holds jobs that are queued, delayed or in flight, so a push is a no-op
+	// until the job is acked, dead-lettered or removed.
+	STREAM_JOBS_SET = "job_stream_jobs"
+)
+
+func streamKey(queue string) string { return queueKey(JOB_STREAM, queue) }
+
+// takeEntryLua acks and deletes the current stream entry of a job, if it has one, and
+// reports whether it did.
+const takeEntryLua = `
+local function takeEntry(stream, entries, group, id)
+  local ref = redis.call('HGET', entries, id)
+  if not ref then
+    return false
+  end
+  local entry = string.match(ref, '|(.+)$')
+  redis.call('XACK', stream, group, entry)
+  redis.call('XDEL', stream, entry)
+  redis.call('HDEL', entries, id)
+  return true
+end
+`
+
+// streamPushScript appends a job to a stream unless it is already queued or in flight.
+var streamPushScript = redis.NewScript(`
+if redis.call('SADD', KEYS[1], ARGV[1]) == 0 then
+  return 0
+end
+local entry = redis.call('XADD', KEYS[2], '*', 'job', ARGV[1])
+redis.call('HSET', KEYS[3], ARGV[1], ARGV[2] .. '|' .. entry)
+redis.call('SADD', KEYS[4], ARGV[2])
+return 1
+`)
+
+// streamSettleScript removes the entry of a job and then, depending on ARGV[3], forgets the
+// job ("ack"), dead-letters it ("dlq"), parks it in the delayed set until ARGV[4] ("delay"),
+// drops it from the delayed set too ("remove") or appends a fresh entry for queue ARGV[5]
+// ("requeue", only if it had an entry).
+var streamSettleScript = redis.NewScript(takeEntryLua + `
+local had = takeEntry(KEYS[1], KEYS[2], ARGV[1], ARGV[2])
+local mode = ARGV[3]
+if mode == 'requeue' then
+  if had then
+    local entry = redis.call('XADD', KEYS[1], '*', 'job', ARGV[2])
+    redis.call('HSET', KEYS[2], ARGV[2], ARGV[5] .. '|' .. entry)
+  end
+  return 1
+end
+if mode == 'delay' then
+  redis.call('ZADD', KEYS[5], tonumber(ARGV[4]), ARGV[2])
+  return 1
+end
+redis.call('SREM', KEYS[3], ARGV[2])
+if mode == 'dlq' then
+  redis.call('RPUSH', KEYS[4], ARGV[2])
+elseif mode == 'remove' then
+  redis.call('ZREM', KEYS[5], ARGV[2])
+end
+return 1
+`)
+
+// streamPromoteScript appends delayed jobs due at ARGV[1] (Unix ms) to the stream.
+var streamPromoteScript = redis.NewScript(fenceLua + `
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, id in ipairs(ids) do
+  redis.call('ZREM', KEYS[1], id)
+  local entry = redis.call('XADD', KEYS[2], '*', 'job', id)
+  redis.call('HSET', KEYS[3], id, ARGV[3] .. '|' .. entry)
+end
+return #ids
+`)
+
+// streamReplayScript moves a job from a DLQ list back onto the stream, doing nothing if the
+// job is no longer dead-lettered.
+var streamReplayScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+  return 0
+end
+redis.call('SADD', KEYS[2], ARGV[1])
+local entry = redis.call('XADD', KEYS[3], '*', 'job', ARGV[1])
+redis.call('HSET', KEYS[4], ARGV[1], ARGV[2] .. '|' .. entry)
+return 1
+`)
+
+// streamRenewScript resets the idle time of an entry while ARGV[2] still owns it, without
+// counting a delivery. It returns 0 once another consumer has claimed the entry.
+var streamRenewScript = redis.NewScript(`
+local pending = redis.call('XPENDING', KEYS[1], ARGV[1], ARGV[3], ARGV[3], 1)
+if #pending == 0 or pending[1][2] ~= ARGV[2] then
+  return 0
+end
+redis.call('XCLAIM', KEYS[1], ARGV[1], ARGV[2], 0, ARGV[3], 'JUSTID')
+return 1
+`)
+
+// StreamQueue keeps each queue in a Redis stream read through the STREAM_GROUP consumer
+// group, with this process as one consumer. An entry stays pending in the group until it
+// is acked; entries idle for LEASE_TTL belong to a consumer that stopped heartbeating and
+// are auto-claimed by the next PopJob, which also counts the delivery.
+//
+// Streams deliver in push order, so this backend ignores job priorities. Dead-letter lists,
+// cancellations and job events are shared with QueueManager.
+type StreamQueue struct {
+	*QueueManager
+	consumer string
+	groups   sync.Map // stream keys whose consumer group exists
+}
+
+func NewStreamQueue(addr string) (*StreamQueue, error) {
+	qm, err := NewQueueManager(addr)
+	if err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	return &StreamQueue{
+		QueueManager: qm,
+		consumer:     fmt.Sprintf("%s-%d", host, os.Getpid()),
+	}, nil
+}
+
+// ensureGroup creates the consumer group of a stream, reading it from the start so entries
+// pushed before the group existed are delivered too.
+func (q *StreamQueue) ensureGroup(ctx context.Context, client *redis.Client, key string) error {
+	if _, ok := q.groups.Load(key); ok {
+		return nil
+	}
+	err := client.XGroupCreateMkStream(ctx, key, STREAM_GROUP, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	q.groups.Store(key, struct{}{})
+	return nil
+}
+
+// entry returns the queue and stream entry ID currently holding jobId, or "" if none.
+func (q *StreamQueue) entry(ctx context.Context, client *redis.Client, jobId string) (string, string, error) {
+	ref, err := client.HGet(ctx, STREAM_ENTRIES_HASH, jobId).Result()
+	if err == redis.Nil {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	queue, id, _ := strings.Cut(ref, "|")
+	return queue, id, nil
+}
+
+// PushJob appends jobId to the stream of queue. Pushing a job that is already queued,
+// delayed or in flight does nothing; priority is ignored.
+func (q *StreamQueue) PushJob(ctx context.Context, queue, jobId string, priority int32) error {
+	client, err := q.ensureConnected(ctx)
+	if err != nil {
+		log.Printf("Connection error in PushJob: %v", err)
+		return err
+	}
+	keys := []string{STREAM_JOBS_SET, streamKey(queue), STREAM_ENTRIES_HASH, QUEUES_SET}
+	if err := streamPushScript.Run(ctx, client, keys, jobId, queueName(queue)).Err(); err != nil {
+		log.Printf("XADD failed for job %s: %v", jobId, err)
+		return err
+	}
+	return nil
+}
+
+// PopJob returns the next job of the first queue in queues that has one. Entries left idle
+// by a dead consumer are claimed before new ones are read. The streams are polled until
+// POP_TIMEOUT.
+func (q *StreamQueue) PopJob(ctx context.Context, queues []string) (string, string, error) {
+	client, err := q.ensureConnected(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	deadline := time.Now().Add(POP_TIMEOUT)
+	for {
+		for _, queue := range queues {
+			jobId, err := q.readOne(ctx, client, streamKey(queue))
+			if err != nil {
+				log.Printf("PopJob: stream read error on %s: %v", queue, err)
+				return "", "", err
+			}
+			if jobId != "" {
+				log.Printf("PopJob: consumer %s took job %s from %s", q.consumer, jobId, queueName(queue))
+				return jobId, queueName(queue), nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			log.Printf("No jobs available in queue after %v timeout", POP_TIMEOUT)
+			return "", "", ErrQueueTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		case <-time.After(POP_POLL_INTERVAL):
+		}
+	}
+}
+
+// readOne claims one idle entry of key or, failing that, reads one new entry. It returns ""
+// if the stream has nothing for this consumer.
+func (q *StreamQueue) readOne(ctx context.Context, client *redis.Client, key string) (string, error) {
+	if err := q.ensureGroup(ctx, client, key); err != nil {
+		return "", err
+	}
+
+	claimed, _, err := client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   key,
+		Group:    STREAM_GROUP,
+		Consumer: q.consumer,
+		MinIdle:  LEASE_TTL,
+		Start:    "0-0",
+		Count:    1,
+	}).Result()
+	if err != nil && err != redis.Nil {
+		q.forgetGroup(key, err)
+		return "", err
+	}
+	if jobId := streamJob(claimed); jobId != "" {
+		log.Printf("PopJob: consumer %s claimed idle job %s", q.consumer, jobId)
+		return jobId, nil
+	}
+
+	streams, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    STREAM_GROUP,
+		Consumer: q.consumer,
+		Streams:  []string{key, ">"},
+		Count:    1,
+		Block:    -1,
+	}).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		q.forgetGroup(key, err)
+		return "", err
+	}
+	for _, s := range streams {
+		if jobId := streamJob(s.Messages); jobId != "" {
+			return jobId, nil
+		}
+	}
+	return "", nil
+}
+
+// forgetGroup drops the cached group of key when Redis lost it, e.g. after a flush.
+func (q *StreamQueue) forgetGroup(key string, err error) {
+	if strings.HasPrefix(err.Error(), "NOGROUP") {
+		q.groups.Delete(key)
+	}
+}
+
+// streamJob returns the job ID of the first message, or "" if there is none.
+func streamJob(msgs []redis.XMessage) string {
+	for _, msg := range msgs {
+		if id, ok := msg.Values["job"].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+func (q *StreamQueue) settle(ctx context.Context, queue, jobId, mode string, until time.Time) error {
+	client, err := q.ensureConnected(ctx)
+	if err != nil {
+		return err
+	}
+	keys := []string{streamKey(queue), STREAM_ENTRIES_HASH, STREAM_JOBS_SET, dlqKey(queue), delayedKey(queue)}
+	return streamSettleScript.Run(ctx, client, keys, STREAM_GROUP, jobId, mode, until.UnixMilli(), queueName(queue)).Err()
+}
+
+// AckProcessing acks and deletes the stream entry of a processed job.
+func (q *StreamQueue) AckProcessing(ctx context.Context, queue, jobId string) error {
+	return q.settle(ctx, queue, jobId, "ack", time.Time{})
+}
+
+// DropDuplicate does nothing: pushes of a job in flight are ignored, so a stream never
+// delivers the same job twice at once.
+func (q *StreamQueue) DropDuplicate(ctx context.Context, queue, jobId string) error {
+	return nil
+}
+
+// Deliveries returns how often the group handed out the current entry of jobId.
+func (q *StreamQueue) Deliveries(ctx context.Context, queue, jobId string) (int64, error) {
+	client, err := q.ensureConnected(ctx)
+	if err != nil {
+		return 0, err
+	}
+	_, entry, err := q.entry(ctx, client, jobId)
+	if err != nil || entry == "" {
+		return 0, err
+	}
+	pending, err := client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: streamKey(queue),
+		Group:  STREAM_GROUP,
+		Start:  entry,
+		End:    entry,
+		Count:  1,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return 0, err
+	}
+	return pending[0].RetryCount, nil
+}
+
+// RequeueFromProcessing acks the entry of jobId and appends a fresh one to the end of its
+// stream. Handing a job back, e.g. from a paused worker, thus starts a new delivery count
+// instead of adding to the old one.
+func (q *StreamQueue) RequeueFromProcessing(ctx context.Context, queue, jobId string) error {
+	return q.settle(ctx, queue, jobId, "requeue", time.Time{})
+}
+
+// MoveToDLQ acks the entry of jobId and appends the job to the DLQ of queue.
+func (q *StreamQueue) MoveToDLQ(ctx context.Context, queue, jobId string) error {
+	return q.settle(ctx, queue, jobId, "dlq", time.Time{})
+}
+
+// RemovePending drops the stream entry of jobId and any delayed run, e.g. after it was
+// cancelled.
+func (q *StreamQueue) RemovePending(ctx context.Context, queue, jobId string) error {
+	return q.settle(ctx, queue, jobId, "remove", time.Time{})
+}
+
+// DelayFromProcessing acks the entry of jobId and parks the job in the delayed set of queue
+// until PromoteDelayed finds it due.
+func (q *StreamQueue) DelayFromProcessing(ctx context.Context, queue, jobId string, until time.Time) error {
+	return q.settle(ctx, queue, jobId, "delay", until)
+}
+
+// PromoteDelayed appends delayed jobs of queue whose time has come to its stream and returns
+// how many were moved. It is fenced like QueueManager.PromoteDelayed.
+func (q *StreamQueue) PromoteDelayed(ctx context.Context, queue string, now time.Time, epoch int64) (int, error) {
+	client, err := q.ensureConnected(ctx)
+	if err != nil {
+		return 0, err
+	}
+	keys := []string{delayedKey(queue), streamKey(queue), STREAM_ENTRIES_HASH, LEADER_EPOCH_KEY}
+	n, err := streamPromoteScript.Run(ctx, client, keys, now.UnixMilli(), PROMOTE_BATCH, queueName(queue), epoch).Int()
+	return fencedCount(n, err, epoch)
+}
+
+// ReplayDeadLetter moves jobId from the DLQ of queue back onto its stream. It returns false
+// if the job was not dead-lettered.
+func (q *StreamQueue) ReplayDeadLetter(ctx context.Context, queue, jobId string, priority int32) (bool, error) {
+	client, err := q.ensureConnected(ctx)
+	if err != nil {
+		return false, err
+	}
+	keys := []string{dlqKey(queue), STREAM_JOBS_SET, streamKey(queue), STREAM_ENTRIES_HASH}
+	n, err := streamReplayScript.Run(ctx, client, keys, jobId, queueName(queue)).Int()
+	return n == 1, err
+}
+
+// RenewLease keeps the entry of jobId from going idle while this consumer works on it.
+// workerId is unused: the owner is the consumer that read the entry.
+func (q *StreamQueue) RenewLease(ctx context.Context, jobId, workerId string) error {
+	client, err := q.ensureConnected(ctx)
+	if err != nil {
+		return err
+	}
+	queue, entry, err := q.entry(ctx, client, jobId)
+	if err != nil || entry == "" {
+		return err
+	}
+	owned, err := streamRenewScript.Run(ctx, client, []string{streamKey(queue)}, STREAM_GROUP, q.consumer, entry).Int()
+	if err != nil {
+		return err
+	}
+	if owned == 0 {
+		return fmt.Errorf("job %s was claimed by another consumer", jobId)
+	}
+	return nil
+}
+
+// ReleaseLease does nothing: acking the entry ends the lease.
+func (q *StreamQueue) ReleaseLease(ctx context.Context, jobId string) error {
+	return nil
+}
+
+// OrphanedJobs returns nothing: workers claim idle entries themselves in PopJob.
+func (q *StreamQueue) OrphanedJobs(ctx context.Context) ([]JobRef, error) {
+	return nil, nil
+}
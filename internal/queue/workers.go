@@ -0,0 +1,173 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// WORKERS_HASH maps worker IDs to their JSON-encoded WorkerInfo.
+	WORKERS_HASH = "workers"
+	// WORKER_CONTROLS_HASH maps worker IDs to the run state they were asked to take.
+	WORKER_CONTROLS_HASH = "worker_controls"
+	// WORKER_LEASE_PREFIX keys the lease a worker renews while it is alive.
+	WORKER_LEASE_PREFIX = "worker_lease:"
+	// WORKER_LEASE_TTL is how long a worker registration stays alive without a heartbeat.
+	WORKER_LEASE_TTL = LEASE_TTL
+	// WORKER_RETENTION is how long a worker whose lease expired is still listed as dead
+	// before it is forgotten.
+	WORKER_RETENTION = 10 * time.Minute
+)
+
+// Run states of a worker, which SetWorkerControl asks it to change.
+const (
+	// WORKER_RUNNING takes jobs as usual.
+	WORKER_RUNNING = "RUNNING"
+	// WORKER_PAUSED takes no new jobs until resumed; in-flight jobs keep running.
+	WORKER_PAUSED = "PAUSED"
+	// WORKER_DRAINING takes no new jobs and exits once its in-flight jobs finished.
+	WORKER_DRAINING = "DRAINING"
+)
+
+// WorkerInfo describes a registered worker. Workers re-register on every heartbeat, which
+// renews their lease and refreshes Running.
+type WorkerInfo struct {
+	ID      string   `json:"id"`
+	Host    string   `json:"host"`
+	PID     int      `json:"pid"`
+	Version string   `json:"version"`
+	Queues  []string `json:"queues"` // subscriptions as name:weight
+	Slots   int      `json:"slots"`
+	Running []string `json:"running"` // IDs of the jobs in flight
+	State   string   `json:"state"`   // WORKER_RUNNING, WORKER_PAUSED or WORKER_DRAINING
+
+	StartedAt time.Time `json:"started_at"`
+	// LastSeen is when the worker last registered; the backend sets it.
+	LastSeen time.Time `json:"last_seen"`
+	// Alive is set by Workers: false means the lease expired without the worker
+	// deregistering, i.e. it died or lost its connection.
+	Alive bool `json:"-"`
+}
+
+// expired reports whether a dead worker was last seen longer than WORKER_RETENTION ago.
+func (w *WorkerInfo) expired(now time.Time) bool {
+	return now.Sub(w.LastSeen) > WORKER_RETENTION
+}
+
+// sortWorkers orders workers by host, then start time.
+func sortWorkers(workers []*WorkerInfo) {
+	sort.Slice(workers, func(i, j int) bool {
+		if workers[i].Host != workers[j].Host {
+			return workers[i].Host < workers[j].Host
+		}
+		return workers[i].StartedAt.Before(workers[j].StartedAt)
+	})
+}
+
+func workerLeaseKey(workerId string) string { return WORKER_LEASE_PREFIX + workerId }
+
+// RegisterWorker records info and renews the worker's lease for WORKER_LEASE_TTL.
+func (m *QueueManager) RegisterWorker(ctx context.Context, info WorkerInfo) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return err
+	}
+	info.LastSeen = time.Now()
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, WORKERS_HASH, info.ID, data)
+		pipe.Set(ctx, workerLeaseKey(info.ID), 1, WORKER_LEASE_TTL)
+		return nil
+	})
+	return err
+}
+
+// DeregisterWorker removes a worker that is shutting down, along with its control.
+func (m *QueueManager) DeregisterWorker(ctx context.Context, workerId string) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, WORKERS_HASH, workerId)
+		pipe.HDel(ctx, WORKER_CONTROLS_HASH, workerId)
+		pipe.Del(ctx, workerLeaseKey(workerId))
+		return nil
+	})
+	return err
+}
+
+// SetWorkerControl asks a worker to take the run state control, which it picks up on its
+// next poll of WorkerControl.
+func (m *QueueManager) SetWorkerControl(ctx context.Context, workerId, control string) error {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return err
+	}
+	return client.HSet(ctx, WORKER_CONTROLS_HASH, workerId, control).Err()
+}
+
+// WorkerControl returns the run state the worker was asked to take, or "" if none.
+func (m *QueueManager) WorkerControl(ctx context.Context, workerId string) (string, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return "", err
+	}
+	control, err := client.HGet(ctx, WORKER_CONTROLS_HASH, workerId).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return control, err
+}
+
+// Workers lists the registered workers, with Alive unset for those whose lease expired.
+// Dead workers past WORKER_RETENTION are dropped from the registry.
+func (m *QueueManager) Workers(ctx context.Context) ([]*WorkerInfo, error) {
+	client, err := m.ensureConnected(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := client.HGetAll(ctx, WORKERS_HASH).Result()
+	if err != nil {
+		return nil, err
+	}
+	var workers []*WorkerInfo
+	for id, data := range entries {
+		info := &WorkerInfo{}
+		if err := json.Unmarshal([]byte(data), info); err != nil {
+			return nil, err
+		}
+		info.ID = id
+		workers = append(workers, info)
+	}
+
+	pipe := client.Pipeline()
+	leases := make([]*redis.IntCmd, len(workers))
+	for i, info := range workers {
+		leases[i] = pipe.Exists(ctx, workerLeaseKey(info.ID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	kept := workers[:0]
+	for i, info := range workers {
+		info.Alive = leases[i].Val() > 0
+		if !info.Alive && info.expired(now) {
+			client.HDel(ctx, WORKERS_HASH, info.ID)
+			client.HDel(ctx, WORKER_CONTROLS_HASH, info.ID)
+			continue
+		}
+		kept = append(kept, info)
+	}
+	sortWorkers(kept)
+	return kept, nil
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+
+	"distributed-task-scheduler/internal/coord"
+	pb "distributed-task-scheduler/proto"
+)
+
+// Election is the leader election a server takes part in; coord.EtcdLeader implements it.
+type Election interface {
+	Status() coord.LeaderStatus
+}
+
+// ClusterServer implements ClusterService on top of a JobServer, its election and the worker
+// registry of its queue.
+type ClusterServer struct {
+	pb.UnimplementedClusterServiceServer
+	jobs     *JobServer
+	backend  string
+	election Election // nil when leader election is disabled
+	nodeID   string
+}
+
+// NewClusterServer reports on jobs, which takes part in election through backend, e.g.
+// "etcd". election is nil when there is none; a server that runs the leader loops anyway,
+// like the standalone mode, is then reported as leader.
+func NewClusterServer(jobs *JobServer, backend string, election Election) *ClusterServer {
+	return &ClusterServer{jobs: jobs, backend: backend, election: election, nodeID: coord.Identity()}
+}
+
+func (c *ClusterServer) GetClusterStatus(ctx context.Context, req *pb.GetClusterStatusRequest) (*pb.ClusterStatus, error) {
+	leader := c.jobs.LeaderState()
+	status := &pb.ClusterStatus{
+		NodeId:              c.nodeID,
+		Role:                "NONE",
+		Election:            c.backend,
+		LeaderDutiesRunning: leader.Running,
+	}
+	if c.election != nil {
+		st := c.election.Status()
+		status.NodeId = st.Identity
+		status.Role = "FOLLOWER"
+		if st.IsLeader {
+			status.Role = "LEADER"
+		}
+		status.LeaderId = st.Leader
+		status.LeaderEpoch = st.Epoch
+		if !st.ChangedAt.IsZero() {
+			status.LeaderChangedAt = st.ChangedAt.Unix()
+		}
+		status.LeaseTtlSeconds = int64(st.LeaseTTL.Seconds())
+	} else if leader.Running {
+		status.Role = "LEADER"
+		status.LeaderId = c.nodeID
+		status.LeaderEpoch = leader.Epoch
+		status.LeaderChangedAt = leader.Since.Unix()
+	}
+	if scan := leader.LastScan; !scan.At.IsZero() {
+		status.LastScan = &pb.MaintenanceScan{
+			At:               scan.At.Unix(),
+			StaleFailed:      scan.StaleFailed,
+			DelayedPromoted:  int32(scan.DelayedPromoted),
+			DueEnqueued:      int32(scan.DueEnqueued),
+			OrphansReclaimed: int32(scan.OrphansReclaimed),
+			WorkflowSkipped:  scan.WorkflowSkipped,
+			WorkflowReleased: int32(scan.WorkflowReleased),
+		}
+	}
+	return status, nil
+}
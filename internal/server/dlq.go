@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"distributed-task-scheduler/internal/db"
+	"distributed-task-scheduler/internal/queue"
+	pb "distributed-task-scheduler/proto"
+)
+
+func (s *JobServer) ListDeadLetters(ctx context.Context, req *pb.ListDeadLettersRequest) (*pb.ListDeadLettersResponse, error) {
+	queues, err := s.deadLetterQueues(ctx, req.Queue)
+	if err != nil {
+		return nil, err
+	}
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultPageSize
+	} else if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	resp := &pb.ListDeadLettersResponse{}
+	for _, q := range queues {
+		ids, err := s.queueMgr.DeadLetters(ctx, q)
+		if err != nil {
+			log.Printf("Error reading DLQ of queue %s: %v", q, err)
+			return nil, err
+		}
+		resp.Total += int32(len(ids))
+		room := limit - len(resp.DeadLetters)
+		if room <= 0 || len(ids) == 0 {
+			continue
+		}
+		if len(ids) > room {
+			ids = ids[:room]
+		}
+		jobs, err := s.dbMgr.GetJobs(ids)
+		if err != nil {
+			log.Printf("Error loading dead-lettered jobs: %v", err)
+			return nil, err
+		}
+		for _, id := range ids {
+			resp.DeadLetters = append(resp.DeadLetters, toDeadLetter(q, id, jobs[id]))
+		}
+	}
+	log.Printf("Listed %d of %d dead letters", len(resp.DeadLetters), resp.Total)
+	return resp, nil
+}
+
+func (s *JobServer) ReplayDeadLetter(ctx context.Context, jobId *pb.JobId) (*pb.JobResponse, error) {
+	if strings.TrimSpace(jobId.Id) == "" {
+		log.Printf("Received empty job ID in replay request")
+		return nil, errors.New("job ID cannot be empty")
+	}
+
+	job, err := s.dbMgr.GetJob(jobId.Id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Job %s not found in database", jobId.Id)
+			return nil, errors.New("job not found")
+		}
+		log.Printf("Error retrieving job %s from database: %v", jobId.Id, err)
+		return nil, err
+	}
+
+	dead, err := s.queueMgr.IsDeadLetter(ctx, job.Queue, job.ID)
+	if err != nil {
+		log.Printf("Error looking up job %s in DLQ: %v", job.ID, err)
+		return nil, err
+	}
+	if !dead {
+		return &pb.JobResponse{
+			JobId:   job.ID,
+			Success: false,
+			Message: "Job is not in the dead-letter queue",
+		}, nil
+	}
+
+	// The reset writes a replay outbox entry; the relay moves the job out of the DLQ
+	replayed, err := s.dbMgr.ReplayJob(job.ID)
+	if err != nil {
+		log.Printf("Failed to reset job %s for replay: %v", job.ID, err)
+		return nil, err
+	}
+	if !replayed {
+		return &pb.JobResponse{
+			JobId:   job.ID,
+			Success: false,
+			Message: fmt.Sprintf("Job has status %s and cannot be replayed", job.Status),
+		}, nil
+	}
+	s.kickOutbox()
+	log.Printf("Job %s replayed from DLQ of queue %s", job.ID, job.Queue)
+	return &pb.JobResponse{
+		JobId:   job.ID,
+		Success: true,
+		Message: "Job requeued",
+	}, nil
+}
+
+func (s *JobServer) PurgeDeadLetters(ctx context.Context, req *pb.PurgeDeadLettersRequest) (*pb.PurgeDeadLettersResponse, error) {
+	if !req.All && len(req.Ids) == 0 && req.FailedBefore == 0 && req.CommandContains == "" {
+		return nil, errors.New("refusing to purge without a filter; set all to purge every dead letter")
+	}
+	queues, err := s.deadLetterQueues(ctx, req.Queue)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(req.Ids))
+	for _, id := range req.Ids {
+		wanted[id] = true
+	}
+	needle := strings.ToLower(req.CommandContains)
+	needJobs := req.FailedBefore > 0 || needle != ""
+
+	resp := &pb.PurgeDeadLettersResponse{}
+	for _, q := range queues {
+		ids, err := s.queueMgr.DeadLetters(ctx, q)
+		if err != nil {
+			log.Printf("Error reading DLQ of queue %s: %v", q, err)
+			return nil, err
+		}
+		if len(wanted) > 0 {
+			ids = filterIDs(ids, wanted)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		var jobs map[string]*db.Job
+		if needJobs {
+			if jobs, err = s.dbMgr.GetJobs(ids); err != nil {
+				log.Printf("Error loading dead-lettered jobs: %v", err)
+				return nil, err
+			}
+		}
+		for _, id := range ids {
+			if needJobs {
+				// Entries without a task row cannot match task-based filters
+				job := jobs[id]
+				if job == nil {
+					continue
+				}
+				if req.FailedBefore > 0 && job.UpdatedAt >= req.FailedBefore {
+					continue
+				}
+				if needle != "" && !strings.Contains(strings.ToLower(job.Command), needle) {
+					continue
+				}
+			}
+			removed, err := s.queueMgr.RemoveDeadLetter(ctx, q, id)
+			if err != nil {
+				log.Printf("Failed to purge job %s from DLQ of queue %s: %v", id, q, err)
+				return resp, err
+			}
+			if removed {
+				resp.Purged++
+			}
+		}
+	}
+	log.Printf("Purged %d dead letters", resp.Purged)
+	return resp, nil
+}
+
+// deadLetterQueues returns the queues a DLQ request applies to: name, or every known queue.
+func (s *JobServer) deadLetterQueues(ctx context.Context, name string) ([]string, error) {
+	if name != "" {
+		if err := queue.ValidateQueueName(name); err != nil {
+			return nil, err
+		}
+		return []string{name}, nil
+	}
+	return s.queueMgr.Queues(ctx)
+}
+
+// filterIDs returns the IDs in ids that are also in wanted, keeping their order.
+func filterIDs(ids []string, wanted map[string]bool) []string {
+	var out []string
+	for _, id := range ids {
+		if wanted[id] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// toDeadLetter converts a DLQ entry into its API representation. job is nil when the task
+// row no longer exists.
+func toDeadLetter(q, id string, job *db.Job) *pb.DeadLetter {
+	dl := &pb.DeadLetter{Id: id, Queue: q}
+	if job == nil {
+		return dl
+	}
+	dl.Command = job.Command
+	dl.Status = job.Status
+	dl.LastOutput = job.Output.String
+	dl.Attempts = job.Retries
+	dl.FailedAt = job.UpdatedAt
+	dl.Priority = job.Priority
+	return dl
+}
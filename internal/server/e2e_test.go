@@ -0,0 +1,566 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"distributed-task-scheduler/internal/coord"
+	"distributed-task-scheduler/internal/db"
+	"distributed-task-scheduler/internal/queue"
+	"distributed-task-scheduler/internal/worker"
+	pb "distributed-task-scheduler/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testLeaderTick stands in for the 15s ticker of StartLeaderLoops, so delayed retries and
+// workflow releases happen within a test.
+const testLeaderTick = 100 * time.Millisecond
+
+// testCluster is a job server and its workers on the in-memory backends, like the
+// standalone mode.
+type testCluster struct {
+	store   *db.MemoryStore
+	queue   *queue.MemoryQueue
+	jobs    *JobServer
+	workers []*worker.Worker
+	done    []chan error
+}
+
+// startCluster runs a job server with its outbox relay and leader loops, plus one worker
+// per ID with the given slots, until the test ends.
+func startCluster(t *testing.T, slots int, workerIds ...string) *testCluster {
+	t.Helper()
+	c := &testCluster{store: db.NewMemoryStore(), queue: queue.NewMemoryQueue()}
+	c.jobs = NewJobServerWith(c.store, c.queue, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		c.jobs.RunOutboxRelay(ctx)
+	}()
+	if err := c.store.AdvanceLeaderEpoch(1, "test", "test"); err != nil {
+		t.Fatalf("AdvanceLeaderEpoch: %v", err)
+	}
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(testLeaderTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := c.jobs.maintain(ctx, 1); err != nil {
+					t.Errorf("maintain: %v", err)
+					return
+				}
+			}
+		}
+	}()
+
+	for _, id := range workerIds {
+		w, err := worker.NewWorkerWith(id, c.store, c.queue, slots, []worker.QueueWeight{{Name: queue.DEFAULT_QUEUE, Weight: 1}})
+		if err != nil {
+			t.Fatalf("NewWorkerWith(%s): %v", id, err)
+		}
+		done := make(chan error, 1)
+		go func() { done <- w.Start(ctx) }()
+		c.workers = append(c.workers, w)
+		c.done = append(c.done, done)
+	}
+
+	t.Cleanup(func() {
+		cancel()
+		for i, w := range c.workers {
+			w.Kill()
+			select {
+			case <-c.done[i]:
+			case <-time.After(10 * time.Second):
+				t.Errorf("worker %s did not stop", workerIds[i])
+			}
+		}
+		wg.Wait()
+	})
+	return c
+}
+
+func (c *testCluster) submit(t *testing.T, job *pb.Job) string {
+	t.Helper()
+	resp, err := c.jobs.SubmitJob(context.Background(), job)
+	if err != nil || !resp.Success {
+		t.Fatalf("SubmitJob(%q) = %v, %v", job.Command, resp, err)
+	}
+	return resp.JobId
+}
+
+func (c *testCluster) status(t *testing.T, id string) *pb.JobStatus {
+	t.Helper()
+	st, err := c.jobs.GetJobStatus(context.Background(), &pb.JobId{Id: id})
+	if err != nil {
+		t.Fatalf("GetJobStatus(%s): %v", id, err)
+	}
+	return st
+}
+
+// waitFor polls until cond holds, failing the test after timeout.
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func (c *testCluster) waitStatus(t *testing.T, id, want string) *pb.JobStatus {
+	t.Helper()
+	var st *pb.JobStatus
+	waitFor(t, 10*time.Second, "job "+id+" to be "+want, func() bool {
+		st = c.status(t, id)
+		return st.Status == want
+	})
+	return st
+}
+
+func (c *testCluster) isDeadLetter(t *testing.T, id string) bool {
+	t.Helper()
+	dead, err := c.queue.IsDeadLetter(context.Background(), queue.DEFAULT_QUEUE, id)
+	if err != nil {
+		t.Fatalf("IsDeadLetter(%s): %v", id, err)
+	}
+	return dead
+}
+
+func (c *testCluster) history(t *testing.T, id string) []*pb.JobAttempt {
+	t.Helper()
+	h, err := c.jobs.GetJobHistory(context.Background(), &pb.JobId{Id: id})
+	if err != nil {
+		t.Fatalf("GetJobHistory(%s): %v", id, err)
+	}
+	return h.Attempts
+}
+
+func TestSubmitRunsToSuccess(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 1, "w1")
+	id := c.submit(t, &pb.Job{Command: "echo hello"})
+
+	st := c.waitStatus(t, id, "SUCCEEDED")
+	if st.Output != "hello\n" || st.Result.GetExitCode() != 0 {
+		t.Fatalf("output %q, result %+v", st.Output, st.Result)
+	}
+	attempts := c.history(t, id)
+	if len(attempts) != 1 || attempts[0].Status != "SUCCEEDED" || attempts[0].WorkerId != "w1" {
+		t.Fatalf("history = %+v, want one SUCCEEDED attempt on w1", attempts)
+	}
+}
+
+func TestIdempotentSubmit(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 1)
+	first := c.submit(t, &pb.Job{Command: "true", IdempotencyKey: "once"})
+	second := c.submit(t, &pb.Job{Command: "true", IdempotencyKey: "once"})
+	if first != second {
+		t.Fatalf("resubmission created job %s, want %s", second, first)
+	}
+	if other := c.submit(t, &pb.Job{Command: "true", IdempotencyKey: "twice"}); other == first {
+		t.Fatal("different keys share a job")
+	}
+}
+
+func TestUnknownJobIsNotFound(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 1)
+	ctx := context.Background()
+	if _, err := c.jobs.GetJobStatus(ctx, &pb.JobId{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("GetJobStatus of an unknown job = %v, want NotFound", err)
+	}
+	if _, err := c.jobs.GetJobHistory(ctx, &pb.JobId{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("GetJobHistory of an unknown job = %v, want NotFound", err)
+	}
+}
+
+func TestFailedJobRetriesThenDeadLetters(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 1, "w1")
+	id := c.submit(t, &pb.Job{
+		Command:     "echo attempt; exit 3",
+		RetryPolicy: &pb.RetryPolicy{MaxAttempts: 2, InitialBackoffSeconds: 1},
+	})
+
+	waitFor(t, 10*time.Second, "job to be dead-lettered", func() bool { return c.isDeadLetter(t, id) })
+	if st := c.status(t, id); st.Status != "FAILED" || st.Result.GetExitCode() != 3 {
+		t.Fatalf("dead-lettered job has status %s, result %+v", st.Status, st.Result)
+	}
+	attempts := c.history(t, id)
+	if len(attempts) != 2 {
+		t.Fatalf("history = %+v, want two attempts", attempts)
+	}
+	for _, a := range attempts {
+		if a.Status != "FAILED" {
+			t.Fatalf("attempt %d has status %s", a.Attempt, a.Status)
+		}
+	}
+	// The retry waited for its backoff (1s minus at most 20% jitter)
+	if gap := attempts[1].StartedAt - attempts[0].StartedAt; gap < 0 {
+		t.Fatalf("retry started %ds before the first attempt", -gap)
+	}
+}
+
+func TestNonRetryableExitCodeDeadLettersAtOnce(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 1, "w1")
+	id := c.submit(t, &pb.Job{
+		Command:     "exit 2",
+		RetryPolicy: &pb.RetryPolicy{MaxAttempts: 5, RetryableExitCodes: []int32{75}},
+	})
+	waitFor(t, 10*time.Second, "job to be dead-lettered", func() bool { return c.isDeadLetter(t, id) })
+	if attempts := c.history(t, id); len(attempts) != 1 {
+		t.Fatalf("history = %+v, want a single attempt", attempts)
+	}
+}
+
+func TestReplayDeadLetterRunsJobAgain(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 1, "w1")
+	marker := filepath.Join(t.TempDir(), "ran")
+	// Fails the first time, succeeds once the marker exists
+	id := c.submit(t, &pb.Job{
+		Command:     "if [ -f " + marker + " ]; then echo again; else touch " + marker + "; exit 1; fi",
+		RetryPolicy: &pb.RetryPolicy{MaxAttempts: 1},
+	})
+	waitFor(t, 10*time.Second, "job to be dead-lettered", func() bool { return c.isDeadLetter(t, id) })
+
+	resp, err := c.jobs.ReplayDeadLetter(context.Background(), &pb.JobId{Id: id})
+	if err != nil || !resp.Success {
+		t.Fatalf("ReplayDeadLetter = %v, %v", resp, err)
+	}
+	st := c.waitStatus(t, id, "SUCCEEDED")
+	if st.Output != "again\n" {
+		t.Fatalf("replayed run printed %q", st.Output)
+	}
+	if c.isDeadLetter(t, id) {
+		t.Fatal("replayed job still in the DLQ")
+	}
+	if resp, _ := c.jobs.ReplayDeadLetter(context.Background(), &pb.JobId{Id: id}); resp.Success {
+		t.Fatal("replayed a job that is not dead-lettered")
+	}
+}
+
+func TestCancelRunningJob(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 1, "w1")
+	id := c.submit(t, &pb.Job{Command: "sleep 30"})
+	c.waitStatus(t, id, "RUNNING")
+
+	started := time.Now()
+	resp, err := c.jobs.CancelJob(context.Background(), &pb.JobId{Id: id})
+	if err != nil || !resp.Success {
+		t.Fatalf("CancelJob = %v, %v", resp, err)
+	}
+	c.waitStatus(t, id, "CANCELLED")
+	if time.Since(started) > 5*time.Second {
+		t.Fatal("cancelled job was not killed")
+	}
+	if c.isDeadLetter(t, id) {
+		t.Fatal("cancelled job was dead-lettered")
+	}
+	if resp, _ := c.jobs.CancelJob(context.Background(), &pb.JobId{Id: id}); resp.Success {
+		t.Fatal("cancelled a finished job")
+	}
+}
+
+func TestCancelPendingJob(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 1) // no workers, so the job stays queued
+	id := c.submit(t, &pb.Job{Command: "true"})
+	resp, err := c.jobs.CancelJob(context.Background(), &pb.JobId{Id: id})
+	if err != nil || !resp.Success {
+		t.Fatalf("CancelJob = %v, %v", resp, err)
+	}
+	if st := c.status(t, id); st.Status != "CANCELLED" {
+		t.Fatalf("status %s, want CANCELLED", st.Status)
+	}
+}
+
+func TestTimedOutJob(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 1, "w1")
+	id := c.submit(t, &pb.Job{
+		Command:        "sleep 30",
+		TimeoutSeconds: 1,
+		RetryPolicy:    &pb.RetryPolicy{MaxAttempts: 1},
+	})
+	waitFor(t, 10*time.Second, "job to be dead-lettered", func() bool { return c.isDeadLetter(t, id) })
+	if st := c.status(t, id); st.Status != "TIMED_OUT" {
+		t.Fatalf("status %s, want TIMED_OUT", st.Status)
+	}
+}
+
+func submitWorkflow(t *testing.T, c *testCluster, nodes ...*pb.WorkflowNode) *pb.WorkflowResponse {
+	t.Helper()
+	resp, err := c.jobs.SubmitWorkflow(context.Background(), &pb.Workflow{Name: t.Name(), Nodes: nodes})
+	if err != nil || !resp.Success {
+		t.Fatalf("SubmitWorkflow = %v, %v", resp, err)
+	}
+	return resp
+}
+
+func (c *testCluster) waitWorkflow(t *testing.T, id, want string) *pb.WorkflowStatus {
+	t.Helper()
+	var st *pb.WorkflowStatus
+	waitFor(t, 10*time.Second, "workflow "+id+" to be "+want, func() bool {
+		var err error
+		st, err = c.jobs.GetWorkflowStatus(context.Background(), &pb.WorkflowId{Id: id})
+		if err != nil {
+			t.Fatalf("GetWorkflowStatus: %v", err)
+		}
+		return st.Status == want
+	})
+	return st
+}
+
+func TestWorkflowRunsInDependencyOrder(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 2, "w1")
+	resp := submitWorkflow(t, c,
+		&pb.WorkflowNode{Name: "extract", Job: &pb.Job{Command: "echo extract"}},
+		&pb.WorkflowNode{Name: "load", Job: &pb.Job{Command: "echo load"}, DependsOn: []string{"extract"}},
+	)
+	c.waitWorkflow(t, resp.WorkflowId, "SUCCEEDED")
+
+	extract := c.history(t, resp.JobIds["extract"])
+	load := c.history(t, resp.JobIds["load"])
+	if len(extract) != 1 || len(load) != 1 {
+		t.Fatalf("attempts: extract %d, load %d; want one each", len(extract), len(load))
+	}
+	if load[0].StartedAt < extract[0].FinishedAt {
+		t.Fatal("load started before extract finished")
+	}
+}
+
+func TestWorkflowFailedParentSkipsChildren(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 2, "w1")
+	resp := submitWorkflow(t, c,
+		&pb.WorkflowNode{Name: "parent", Job: &pb.Job{Command: "exit 1", RetryPolicy: &pb.RetryPolicy{MaxAttempts: 1}}},
+		&pb.WorkflowNode{Name: "child", Job: &pb.Job{Command: "echo child"}, DependsOn: []string{"parent"}},
+		&pb.WorkflowNode{Name: "grandchild", Job: &pb.Job{Command: "echo grandchild"}, DependsOn: []string{"child"}},
+		&pb.WorkflowNode{Name: "sibling", Job: &pb.Job{Command: "echo sibling"}},
+	)
+	st := c.waitWorkflow(t, resp.WorkflowId, "FAILED")
+
+	want := map[string]string{"parent": "FAILED", "child": "SKIPPED", "grandchild": "SKIPPED", "sibling": "SUCCEEDED"}
+	waitFor(t, 10*time.Second, "every node to settle", func() bool {
+		st, _ = c.jobs.GetWorkflowStatus(context.Background(), &pb.WorkflowId{Id: resp.WorkflowId})
+		for _, n := range st.Nodes {
+			if n.Status != want[n.Name] {
+				return false
+			}
+		}
+		return true
+	})
+	for _, name := range []string{"child", "grandchild"} {
+		if attempts := c.history(t, resp.JobIds[name]); len(attempts) != 0 {
+			t.Fatalf("skipped node %s ran %d times", name, len(attempts))
+		}
+	}
+}
+
+func TestReplayedParentRunsSkippedChildren(t *testing.T) {
+	t.Parallel()
+	c := startCluster(t, 2, "w1")
+	marker := filepath.Join(t.TempDir(), "ran")
+	resp := submitWorkflow(t, c,
+		&pb.WorkflowNode{Name: "parent", Job: &pb.Job{
+			Command:     "if [ -f " + marker + " ]; then echo again; else touch " + marker + "; exit 1; fi",
+			RetryPolicy: &pb.RetryPolicy{MaxAttempts: 1},
+		}},
+		&pb.WorkflowNode{Name: "child", Job: &pb.Job{Command: "echo child"}, DependsOn: []string{"parent"}},
+		&pb.WorkflowNode{Name: "grandchild", Job: &pb.Job{Command: "echo grandchild"}, DependsOn: []string{"child"}},
+	)
+	parent := resp.JobIds["parent"]
+	waitFor(t, 10*time.Second, "parent to be dead-lettered", func() bool { return c.isDeadLetter(t, parent) })
+	c.waitStatus(t, resp.JobIds["grandchild"], "SKIPPED")
+
+	replay, err := c.jobs.ReplayDeadLetter(context.Background(), &pb.JobId{Id: parent})
+	if err != nil || !replay.Success {
+		t.Fatalf("ReplayDeadLetter = %v, %v", replay, err)
+	}
+	c.waitWorkflow(t, resp.WorkflowId, "SUCCEEDED")
+	if st := c.waitStatus(t, resp.JobIds["grandchild"], "SUCCEEDED"); st.Output != "grandchild\n" {
+		t.Fatalf("grandchild printed %q", st.Output)
+	}
+}
+
+func TestLeaderReclaimsOrphanedJobs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := startCluster(t, 1) // the test plays a worker that dies
+	id := c.submit(t, &pb.Job{Command: "true", RetryPolicy: &pb.RetryPolicy{MaxAttempts: 2}})
+
+	// crash pops the job and marks it RUNNING without ever taking a lease
+	crash := func() queue.JobRef {
+		t.Helper()
+		popCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		popped, q, err := c.queue.PopJob(popCtx, []string{queue.DEFAULT_QUEUE})
+		if err != nil || popped != id {
+			t.Fatalf("PopJob = %s, %v; want %s", popped, err, id)
+		}
+		if attempt, _ := c.store.ClaimJob(id, "dead-worker", "host"); attempt == 0 {
+			t.Fatal("ClaimJob failed")
+		}
+		return queue.JobRef{Queue: q, ID: id}
+	}
+	// reclaim runs the orphan scan as if the entry had been lease-less for a full TTL
+	reclaim := func(ref queue.JobRef) {
+		t.Helper()
+		c.jobs.orphanSince = map[queue.JobRef]time.Time{ref: time.Now().Add(-queue.LEASE_TTL)}
+		if n, err := c.jobs.reclaimOrphanedJobs(ctx, 1); err != nil || n != 1 {
+			t.Fatalf("reclaimOrphanedJobs = %d, %v; want 1", n, err)
+		}
+	}
+
+	ref := crash()
+	// A fresh orphan is given LEASE_TTL to take its lease
+	if n, _ := c.jobs.reclaimOrphanedJobs(ctx, 1); n != 0 {
+		t.Fatalf("reclaimed %d entries without waiting", n)
+	}
+	reclaim(ref)
+	if st := c.status(t, id); st.Status != "PENDING" {
+		t.Fatalf("status after the first reclaim %s, want PENDING", st.Status)
+	}
+
+	reclaim(crash())
+	if st := c.status(t, id); st.Status != "FAILED" || !c.isDeadLetter(t, id) {
+		t.Fatalf("status after the last reclaim %s, want FAILED in the DLQ", st.Status)
+	}
+	attempts := c.history(t, id)
+	if len(attempts) != 2 || attempts[0].Status != "FAILED" || attempts[1].Status != "FAILED" {
+		t.Fatalf("history = %+v, want two FAILED attempts", attempts)
+	}
+}
+
+func TestReplacedLeaderStops(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store, q := db.NewMemoryStore(), queue.NewMemoryQueue()
+	s := NewJobServerWith(store, q, time.Hour)
+	if err := store.AdvanceLeaderEpoch(1, "old", "test"); err != nil {
+		t.Fatalf("AdvanceLeaderEpoch(1): %v", err)
+	}
+	if _, err := s.maintain(ctx, 1); err != nil {
+		t.Fatalf("maintain as leader: %v", err)
+	}
+
+	// A new leader registers its epoch and promotes delayed jobs
+	if err := store.AdvanceLeaderEpoch(2, "new", "test"); err != nil {
+		t.Fatalf("AdvanceLeaderEpoch(2): %v", err)
+	}
+	if _, err := q.PromoteDelayed(ctx, queue.DEFAULT_QUEUE, time.Now(), 2); err != nil {
+		t.Fatalf("PromoteDelayed(2): %v", err)
+	}
+	if _, err := s.maintain(ctx, 1); !errors.Is(err, db.ErrStaleLeader) {
+		t.Fatalf("maintain of the old leader = %v, want db.ErrStaleLeader", err)
+	}
+	if _, err := s.promoteDelayedJobs(ctx, 1); !errors.Is(err, queue.ErrStaleLeader) {
+		t.Fatalf("promoteDelayedJobs of the old leader = %v, want queue.ErrStaleLeader", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.StartLeaderLoops(ctx, "test", 1)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("StartLeaderLoops kept running with a stale epoch")
+	}
+	if s.LeaderState().Running {
+		t.Fatal("stale leader reported as running")
+	}
+}
+
+func TestLeaderOfAnotherBackendStops(t *testing.T) {
+	t.Parallel()
+	store := db.NewMemoryStore()
+	s := NewJobServerWith(store, queue.NewMemoryQueue(), time.Hour)
+	if err := store.AdvanceLeaderEpoch(40, "pg-leader", coord.COORD_POSTGRES); err != nil {
+		t.Fatalf("AdvanceLeaderEpoch(40): %v", err)
+	}
+
+	// An etcd revision below the fence a Postgres election left behind is not retried
+	done := make(chan struct{})
+	go func() {
+		s.StartLeaderLoops(context.Background(), coord.COORD_ETCD, 7)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("StartLeaderLoops kept running behind the fence of another backend")
+	}
+	if s.LeaderState().Running {
+		t.Fatal("leader behind a foreign fence reported as running")
+	}
+}
+
+func TestPauseResumeAndDrainWorker(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := startCluster(t, 1, "w1")
+	cluster := NewClusterServer(c.jobs, "", nil)
+	waitFor(t, 5*time.Second, "w1 to register", func() bool {
+		resp, _ := cluster.ListWorkers(ctx, &pb.ListWorkersRequest{})
+		return len(resp.GetWorkers()) == 1
+	})
+
+	if resp, err := cluster.PauseWorker(ctx, &pb.WorkerControlRequest{WorkerId: "w1"}); err != nil || !resp.Success {
+		t.Fatalf("PauseWorker = %v, %v", resp, err)
+	}
+	waitFor(t, 10*time.Second, "w1 to pause", func() bool {
+		resp, _ := cluster.ListWorkers(ctx, &pb.ListWorkersRequest{})
+		return len(resp.GetWorkers()) == 1 && resp.Workers[0].State == queue.WORKER_PAUSED
+	})
+	id := c.submit(t, &pb.Job{Command: "true"})
+	time.Sleep(500 * time.Millisecond)
+	if st := c.status(t, id); st.Status != "PENDING" {
+		t.Fatalf("paused worker ran a job: %s", st.Status)
+	}
+
+	if resp, err := cluster.ResumeWorker(ctx, &pb.WorkerControlRequest{WorkerId: "w1"}); err != nil || !resp.Success {
+		t.Fatalf("ResumeWorker = %v, %v", resp, err)
+	}
+	c.waitStatus(t, id, "SUCCEEDED")
+
+	if resp, err := cluster.DrainWorker(ctx, &pb.WorkerControlRequest{WorkerId: "w1"}); err != nil || !resp.Success {
+		t.Fatalf("DrainWorker = %v, %v", resp, err)
+	}
+	select {
+	case err := <-c.done[0]:
+		if err != nil {
+			t.Fatalf("drained worker returned %v", err)
+		}
+		c.done[0] <- nil // for the cleanup
+	case <-time.After(10 * time.Second):
+		t.Fatal("drained worker did not exit")
+	}
+	if resp, _ := cluster.ListWorkers(ctx, &pb.ListWorkersRequest{IncludeDead: true}); len(resp.GetWorkers()) != 0 {
+		t.Fatalf("drained worker still registered: %+v", resp.Workers)
+	}
+	if _, err := cluster.PauseWorker(ctx, &pb.WorkerControlRequest{WorkerId: "w1"}); err == nil {
+		t.Fatal("paused a worker that left")
+	}
+}
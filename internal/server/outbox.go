@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"distributed-task-scheduler/internal/db"
+)
+
+const (
+	// outboxPollInterval is how often the relay looks for outbox entries when not woken up.
+	outboxPollInterval = time.Second
+	outboxBatchSize    = 100
+)
+
+// RunOutboxRelay publishes outbox entries to their Redis queues until ctx is done. Every
+// server runs it; SKIP LOCKED keeps relays from publishing the same entry concurrently.
+func (s *JobServer) RunOutboxRelay(ctx context.Context) {
+	log.Printf("Outbox relay started")
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+	for {
+		// Drain full batches right away, then wait for a wake-up or the next tick
+		for s.relayOutbox(ctx) == outboxBatchSize {
+		}
+		select {
+		case <-ctx.Done():
+			log.Printf("Outbox relay stopping: context done")
+			return
+		case <-s.outboxKick:
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayOutbox publishes one batch and returns the number of entries published.
+func (s *JobServer) relayOutbox(ctx context.Context) int {
+	n, err := s.dbMgr.RelayOutbox(outboxBatchSize, func(e *db.OutboxEntry) error {
+		if err := s.publishOutbox(ctx, e); err != nil {
+			log.Printf("Outbox relay push error for %s (attempt %d): %v", e.TaskID, e.Attempts+1, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Outbox relay error: %v", err)
+		return 0
+	}
+	return n
+}
+
+// publishOutbox pushes the task of e to its queue. A replay entry moves the task out of its
+// DLQ instead; if it is no longer dead-lettered it is pushed like any other entry.
+func (s *JobServer) publishOutbox(ctx context.Context, e *db.OutboxEntry) error {
+	if e.Replay {
+		moved, err := s.queueMgr.ReplayDeadLetter(ctx, e.Queue, e.TaskID, e.Priority)
+		if err != nil || moved {
+			return err
+		}
+	}
+	return s.queueMgr.PushJob(ctx, e.Queue, e.TaskID, e.Priority)
+}
+
+// kickOutbox wakes the relay so a freshly written outbox entry is published without
+// waiting for the next poll.
+func (s *JobServer) kickOutbox() {
+	select {
+	case s.outboxKick <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// pageCursor identifies the last row of a ListJobs page. It also records the ordering
+// so a token cannot be replayed against a differently sorted listing.
+type pageCursor struct {
+	OrderBy   string `json:"o"`
+	Ascending bool   `json:"a,omitempty"`
+	Value     int64  `json:"v"`
+	ID        string `json:"i"`
+}
+
+func encodePageToken(c pageCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePageToken(token string) (pageCursor, error) {
+	var c pageCursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, errors.New("invalid page token")
+	}
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return c, errors.New("invalid page token")
+	}
+	return c, nil
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"distributed-task-scheduler/internal/db"
+	"distributed-task-scheduler/internal/queue"
+	pb "distributed-task-scheduler/proto"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	want := pageCursor{OrderBy: "updated_at", Ascending: true, Value: 42, ID: "job"}
+	got, err := decodePageToken(encodePageToken(want))
+	if err != nil || got != want {
+		t.Fatalf("decodePageToken(encodePageToken(%+v)) = %+v, %v", want, got, err)
+	}
+}
+
+func TestDecodePageTokenRejectsGarbage(t *testing.T) {
+	b64 := base64.RawURLEncoding.EncodeToString
+	for name, token := range map[string]string{
+		"not base64":     "!!!",
+		"padded base64":  base64.URLEncoding.EncodeToString([]byte(`{"o":"created_at","v":1,"i":"x"}`)),
+		"not json":       b64([]byte("created_at:1:x")),
+		"wrong types":    b64([]byte(`{"o":1,"v":"x","i":"x"}`)),
+		"missing job id": b64([]byte(`{"o":"created_at","v":1}`)),
+	} {
+		if _, err := decodePageToken(token); err == nil {
+			t.Errorf("%s: decodePageToken(%q) accepted", name, token)
+		}
+	}
+}
+
+func TestListJobsPagesAndRejectsForeignTokens(t *testing.T) {
+	ctx := context.Background()
+	s := NewJobServerWith(db.NewMemoryStore(), queue.NewMemoryQueue(), time.Hour)
+	for i := 0; i < 5; i++ {
+		if resp, err := s.SubmitJob(ctx, &pb.Job{Command: fmt.Sprintf("echo %d", i)}); err != nil || !resp.Success {
+			t.Fatalf("SubmitJob = %v, %v", resp, err)
+		}
+	}
+
+	seen := map[string]bool{}
+	req := &pb.ListJobsRequest{PageSize: 2}
+	var token string
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination does not end")
+		}
+		resp, err := s.ListJobs(ctx, req)
+		if err != nil {
+			t.Fatalf("ListJobs: %v", err)
+		}
+		for _, j := range resp.Jobs {
+			if seen[j.Id] {
+				t.Fatalf("job %s listed twice", j.Id)
+			}
+			seen[j.Id] = true
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		token = resp.NextPageToken
+		req.PageToken = token
+	}
+	if len(seen) != 5 {
+		t.Fatalf("listed %d jobs, want 5", len(seen))
+	}
+
+	// A token only continues the listing it came from
+	for name, req := range map[string]*pb.ListJobsRequest{
+		"other order":     {PageToken: token, OrderBy: "updated_at"},
+		"other direction": {PageToken: token, Ascending: true},
+		"tampered":        {PageToken: token[:len(token)-2]},
+	} {
+		if _, err := s.ListJobs(ctx, req); err == nil {
+			t.Errorf("%s: ListJobs accepted the token", name)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"distributed-task-scheduler/internal/queue"
+	pb "distributed-task-scheduler/proto"
+)
+
+func TestScheduleRecordRejectsInvalidJobs(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name string
+		job  *pb.Job
+		want string
+	}{
+		{"timezone without cron", &pb.Job{Timezone: "UTC"}, "timezone requires a cron expression"},
+		{"negative schedule time", &pb.Job{ScheduleTime: -1}, "schedule time cannot be negative"},
+		{"negative timeout", &pb.Job{TimeoutSeconds: -1}, "timeout must be between"},
+		{"timeout overflow", &pb.Job{TimeoutSeconds: 1 << 31}, "timeout must be between"},
+		{"invalid queue", &pb.Job{Queue: "no spaces"}, "queue"},
+		{"too few fields", &pb.Job{CronExpr: "* * *"}, "invalid cron expression"},
+		{"out of range minute", &pb.Job{CronExpr: "60 * * * *"}, "invalid cron expression"},
+		{"unknown descriptor", &pb.Job{CronExpr: "@fortnightly"}, "invalid cron expression"},
+		{"never fires", &pb.Job{CronExpr: "0 0 30 2 *"}, "never fires"},
+		{"unknown timezone", &pb.Job{CronExpr: "0 * * * *", Timezone: "Mars/Olympus_Mons"}, "invalid timezone"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := scheduleRecord(tc.job, now)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("scheduleRecord = %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestScheduleRecord(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	record, err := scheduleRecord(&pb.Job{ScheduleTime: now.Add(-time.Hour).Unix()}, now)
+	if err != nil || record.ExecuteAt.Valid || record.Queue != queue.DEFAULT_QUEUE {
+		t.Fatalf("past schedule time = %+v, %v; want an immediate job on the default queue", record, err)
+	}
+
+	later := now.Add(time.Hour)
+	record, err = scheduleRecord(&pb.Job{ScheduleTime: later.Unix(), Queue: "batch"}, now)
+	if err != nil || !record.ExecuteAt.Time.Equal(later) || record.Queue != "batch" {
+		t.Fatalf("future schedule time = %+v, %v", record, err)
+	}
+
+	// schedule_time only delays the first run of a cron job
+	record, err = scheduleRecord(&pb.Job{CronExpr: " 0 * * * * ", ScheduleTime: later.Add(time.Minute).Unix()}, now)
+	if err != nil {
+		t.Fatalf("scheduleRecord: %v", err)
+	}
+	if want := now.Add(2 * time.Hour); !record.NextRunAt.Time.Equal(want) || record.CronExpr.String != "0 * * * *" {
+		t.Fatalf("cron record = %+v, want next run at %v", record, want)
+	}
+}
+
+func TestNextCronRunAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tz database: %v", err)
+	}
+	for _, tc := range []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{
+			"wall clock kept across spring forward",
+			"0 9 * * *", time.Date(2026, 3, 7, 12, 0, 0, 0, ny),
+			time.Date(2026, 3, 8, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			"skipped hour does not fire",
+			"30 2 * * *", time.Date(2026, 3, 7, 12, 0, 0, 0, ny),
+			time.Date(2026, 3, 9, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			"repeated hour fires in daylight time",
+			"30 1 * * *", time.Date(2026, 10, 31, 12, 0, 0, 0, ny),
+			time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC),
+		},
+		{
+			"and again in standard time",
+			"30 1 * * *", time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC),
+			time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			"wall clock kept across fall back",
+			"0 9 * * *", time.Date(2026, 10, 31, 12, 0, 0, 0, ny),
+			time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			next, err := nextCronRun(tc.expr, "America/New_York", tc.after)
+			if err != nil || !next.Equal(tc.want) {
+				t.Fatalf("nextCronRun(%q, %v) = %v, %v; want %v", tc.expr, tc.after, next.UTC(), err, tc.want)
+			}
+		})
+	}
+}
+
+func TestNextCronRunIsStrictlyAfter(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	next, err := nextCronRun("0 12 * * *", "UTC", at)
+	if err != nil || !next.Equal(at.AddDate(0, 0, 1)) {
+		t.Fatalf("nextCronRun at an activation = %v, %v; want the next day", next, err)
+	}
+	// Six fields carry seconds
+	next, err = nextCronRun("30 0 12 * * *", "UTC", at)
+	if err != nil || !next.Equal(at.Add(30*time.Second)) {
+		t.Fatalf("nextCronRun with seconds = %v, %v", next, err)
+	}
+}
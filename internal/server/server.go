@@ -4,55 +4,161 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
+	"math"
 	"strings"
+	"sync"
 	"time"
 
+	"distributed-task-scheduler/internal/coord"
 	"distributed-task-scheduler/internal/db"
 	"distributed-task-scheduler/internal/queue"
 	pb "distributed-task-scheduler/proto"
 
 	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+
+	// Retry policy defaults for fields left at zero
+	defaultMaxAttempts       = 4
+	defaultBackoffSeconds    = 1
+	defaultBackoffMultiplier = 2
+	defaultMaxBackoffSeconds = 300
+
+	maxIdempotencyKeyLen = 255
+)
+
+// validStatuses lists the values allowed in the tasks.status column.
+var validStatuses = map[string]bool{
+	"PENDING":   true,
+	"RUNNING":   true,
+	"SUCCEEDED": true,
+	"FAILED":    true,
+	"CANCELLED": true,
+	"TIMED_OUT": true,
+	"BLOCKED":   true,
+	"SKIPPED":   true,
+}
+
+// cronParser accepts standard five-field expressions with an optional leading seconds field.
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
 type JobServer struct {
 	pb.UnimplementedJobServiceServer
-	dbMgr      *db.DBManager
-	queueMgr   *queue.QueueManager
+	dbMgr      db.Store
+	queueMgr   queue.Backend
 	stopLeader chan struct{}
+	// outboxKick wakes the outbox relay after a submission.
+	outboxKick chan struct{}
+
+	// idempotencyWindow is how long an idempotency key maps to the job first submitted with it.
+	idempotencyWindow time.Duration
+
+	// orphanSince records when the leader first saw a processing entry without a lease.
+	orphanSince map[queue.JobRef]time.Time
+
+	leaderMu sync.Mutex
+	leader   LeaderState
 }
 
-func NewJobServer(dsn string, redisAddr string) (*JobServer, error) {
-	log.Printf("Initializing job server with DB: %s, Redis: %s", dsn, redisAddr)
+// NewJobServer creates a job server whose queues live in the given backend (see queue.Open).
+// Resubmissions with the same idempotency key within idempotencyWindow return the original job.
+// With autoMigrate set it applies pending schema migrations; otherwise it refuses to start
+// until they are applied.
+func NewJobServer(dsn string, queueBackend string, redisAddr string, idempotencyWindow time.Duration, autoMigrate bool) (*JobServer, error) {
+	log.Printf("Initializing job server with DB: %s, queue backend: %s, Redis: %s, idempotency window: %s", dsn, queueBackend, redisAddr, idempotencyWindow)
 
-	dbMgr, err := db.NewDBManager(dsn)
+	dbMgr, err := db.NewDBManager(dsn, autoMigrate)
 	if err != nil {
 		log.Printf("Failed to initialize database: %v", err)
 		return nil, err
 	}
 
-	queueMgr, err := queue.NewQueueManager(redisAddr)
+	queueMgr, err := queue.Open(queueBackend, redisAddr, dsn)
 	if err != nil {
-		log.Printf("Failed to initialize Redis queue: %v", err)
+		log.Printf("Failed to initialize queue: %v", err)
 		dbMgr.Close() // Clean up database connection
 		return nil, err
 	}
 
 	log.Printf("Job server initialized successfully")
+	return NewJobServerWith(dbMgr, queueMgr, idempotencyWindow), nil
+}
+
+// NewJobServerWith creates a job server on an existing store and queue, e.g. the in-memory
+// ones of the standalone mode. Closing the server closes both.
+func NewJobServerWith(store db.Store, queueMgr queue.Backend, idempotencyWindow time.Duration) *JobServer {
 	return &JobServer{
-		dbMgr:      dbMgr,
-		queueMgr:   queueMgr,
-		stopLeader: make(chan struct{}),
-	}, nil
+		dbMgr:             store,
+		queueMgr:          queueMgr,
+		stopLeader:        make(chan struct{}),
+		outboxKick:        make(chan struct{}, 1),
+		idempotencyWindow: idempotencyWindow,
+		orphanSince:       make(map[queue.JobRef]time.Time),
+	}
 }
 
-// StartLeaderLoops runs leader-only maintenance until stop is closed or ctx done.
-func (s *JobServer) StartLeaderLoops(ctx context.Context) {
-	log.Printf("Leader duties started")
+// MaintenanceScan counts what one tick of the leader loops did.
+type MaintenanceScan struct {
+	At               time.Time
+	StaleFailed      int64
+	DelayedPromoted  int
+	DueEnqueued      int
+	OrphansReclaimed int
+	WorkflowSkipped  int64
+	WorkflowReleased int
+}
+
+// LeaderState describes the leader loops of this server.
+type LeaderState struct {
+	Running bool
+	Epoch   int64
+	Since   time.Time
+	// LastScan is the latest finished tick, also kept after the loops stopped.
+	LastScan MaintenanceScan
+}
+
+// LeaderState returns the state of this server's leader loops.
+func (s *JobServer) LeaderState() LeaderState {
+	s.leaderMu.Lock()
+	defer s.leaderMu.Unlock()
+	return s.leader
+}
+
+// StartLeaderLoops runs leader-only maintenance until stop is closed, ctx is done or a newer
+// leader takes over. epoch is the fencing token of this leadership term: it is registered
+// first and passed to every leader-only write, so the writes of a replaced leader that has
+// not noticed yet are rejected with db.ErrStaleLeader. coordinator names the election
+// backend that issued epoch (see coord.COORD_ETCD). Returning hands leadership back to the
+// elector, which resigns.
+func (s *JobServer) StartLeaderLoops(ctx context.Context, coordinator string, epoch int64) {
+	if err := s.advanceEpoch(ctx, coordinator, epoch); errors.Is(err, db.ErrFenceAhead) {
+		log.Printf("Leader duties not started for epoch %d: %v. Epochs of different election "+
+			"backends are unrelated: stop every server and reset the fence as described in the README", epoch, err)
+		return
+	} else if err != nil {
+		log.Printf("Leader duties not started for epoch %d: %v", epoch, err)
+		return
+	}
+	log.Printf("Leader duties started (epoch %d)", epoch)
+	s.leaderMu.Lock()
+	s.leader.Running, s.leader.Epoch, s.leader.Since = true, epoch, time.Now()
+	s.leaderMu.Unlock()
+	defer func() {
+		s.leaderMu.Lock()
+		s.leader.Running = false
+		s.leaderMu.Unlock()
+	}()
+
 	ticker := time.NewTicker(15 * time.Second)
 	defer ticker.Stop()
-	parser := cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
 	for {
 		select {
 		case <-ctx.Done():
@@ -62,34 +168,220 @@ func (s *JobServer) StartLeaderLoops(ctx context.Context) {
 			log.Printf("Leader duties stopping: stop signal")
 			return
 		case <-ticker.C:
-			// 1) Mark stale RUNNING jobs as FAILED after 10 minutes of inactivity
-			if n, err := s.dbMgr.MarkStaleRunningJobsFailed(600); err != nil {
-				log.Printf("Leader maintenance error: %v", err)
-			} else if n > 0 {
-				log.Printf("Leader maintenance: marked %d stale RUNNING jobs as FAILED", n)
+			scan, err := s.maintain(ctx, epoch)
+			if err != nil {
+				log.Printf("Leader duties stopping: %v", err)
+				return
 			}
-			// 2) Enqueue due one-time and recurring tasks
-			ids, err := s.dbMgr.GetDueTaskIDs(100)
+			s.leaderMu.Lock()
+			s.leader.LastScan = scan
+			s.leaderMu.Unlock()
+		}
+	}
+}
+
+// advanceEpoch registers epoch, retrying transient errors with backoff. It gives up on
+// db.ErrStaleLeader and db.ErrFenceAhead, when ctx is done or when the leader loops are
+// stopped.
+func (s *JobServer) advanceEpoch(ctx context.Context, coordinator string, epoch int64) error {
+	backoff := time.Second
+	for {
+		err := s.dbMgr.AdvanceLeaderEpoch(epoch, coord.Identity(), coordinator)
+		if err == nil || errors.Is(err, db.ErrStaleLeader) || errors.Is(err, db.ErrFenceAhead) {
+			return err
+		}
+		log.Printf("Failed to register leader epoch %d, retrying in %v: %v", epoch, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.stopLeader:
+			return errors.New("stop signal")
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// maintain runs one tick of the leader loops. Errors are logged, except db.ErrStaleLeader
+// and queue.ErrStaleLeader, which are returned because this server must stop acting as
+// leader.
+func (s *JobServer) maintain(ctx context.Context, epoch int64) (MaintenanceScan, error) {
+	scan := MaintenanceScan{}
+	// 1) Mark stale RUNNING jobs as FAILED after 10 minutes of inactivity
+	if n, err := s.dbMgr.MarkStaleRunningJobsFailed(600, epoch); errors.Is(err, db.ErrStaleLeader) {
+		return scan, err
+	} else if err != nil {
+		log.Printf("Leader maintenance error: %v", err)
+	} else if n > 0 {
+		scan.StaleFailed = n
+		log.Printf("Leader maintenance: marked %d stale RUNNING jobs as FAILED", n)
+	}
+	// 2) Move retries whose backoff has expired back to their pending queues
+	n, err := s.promoteDelayedJobs(ctx, epoch)
+	if err != nil {
+		return scan, err
+	}
+	scan.DelayedPromoted = n
+	// 3) Enqueue due one-time and recurring tasks through the outbox
+	if n, err = s.enqueueDueTasks(epoch); err != nil {
+		return scan, err
+	}
+	scan.DueEnqueued = n
+	// 4) Reclaim processing entries whose worker stopped heartbeating
+	if n, err = s.reclaimOrphanedJobs(ctx, epoch); err != nil {
+		return scan, err
+	}
+	scan.OrphansReclaimed = n
+	// 5) Release or skip workflow jobs waiting on their dependencies
+	if scan.WorkflowSkipped, scan.WorkflowReleased, err = s.releaseWorkflowJobs(epoch); err != nil {
+		return scan, err
+	}
+	scan.At = time.Now()
+	return scan, nil
+}
+
+// enqueueDueTasks queues due one-time and recurring tasks through the outbox and returns how
+// many it queued. Like maintain, it only returns db.ErrStaleLeader.
+func (s *JobServer) enqueueDueTasks(epoch int64) (int, error) {
+	ids, err := s.dbMgr.GetDueTaskIDs(100)
+	if err != nil {
+		log.Printf("Leader enqueue scan error: %v", err)
+		return 0, nil
+	}
+	queued := 0
+	for _, id := range ids {
+		job, err := s.dbMgr.GetJob(id)
+		if err != nil {
+			log.Printf("Leader enqueue lookup error for %s: %v", id, err)
+			continue
+		}
+		// cron: advance next_run_at with the enqueue so this run is not picked up twice
+		var next time.Time
+		if job.CronExpr.Valid {
+			next, err = nextCronRun(job.CronExpr.String, job.Timezone.String, time.Now())
 			if err != nil {
-				log.Printf("Leader enqueue scan error: %v", err)
+				log.Printf("Leader enqueue: invalid cron expression for %s: %v", id, err)
 				continue
 			}
-			for _, id := range ids {
-				if err := s.queueMgr.PushJob(ctx, id); err != nil {
-					log.Printf("Leader enqueue push error for %s: %v", id, err)
-					continue
-				}
-				_ = s.dbMgr.ClearExecuteAt(id)
-				// cron: compute next
-				job, jerr := s.dbMgr.GetJob(id)
-				if jerr == nil && job.CronExpr.Valid {
-					if sched, perr := parser.Parse(job.CronExpr.String); perr == nil {
-						next := sched.Next(time.Now())
-						_ = s.dbMgr.UpdateNextRun(id, next)
-					}
-				}
-			}
 		}
+		if ok, err := s.dbMgr.EnqueueDueTask(job, next, epoch); errors.Is(err, db.ErrStaleLeader) {
+			return queued, err
+		} else if err != nil {
+			log.Printf("Leader enqueue error for %s: %v", id, err)
+		} else if ok {
+			queued++
+		}
+	}
+	if len(ids) > 0 {
+		s.kickOutbox()
+	}
+	return queued, nil
+}
+
+// promoteDelayedJobs re-enqueues failed jobs from every queue's delayed set once due and
+// returns how many it moved. Like maintain, it only returns queue.ErrStaleLeader.
+func (s *JobServer) promoteDelayedJobs(ctx context.Context, epoch int64) (int, error) {
+	queues, err := s.queueMgr.Queues(ctx)
+	if err != nil {
+		log.Printf("Leader retry scan error: %v", err)
+		return 0, nil
+	}
+	now := time.Now()
+	total := 0
+	for _, q := range queues {
+		n, err := s.queueMgr.PromoteDelayed(ctx, q, now, epoch)
+		if errors.Is(err, queue.ErrStaleLeader) {
+			return total, err
+		} else if err != nil {
+			log.Printf("Leader retry promote error for queue %s: %v", q, err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("Leader retry: moved %d delayed jobs back to queue %s", n, q)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// reclaimOrphanedJobs requeues or dead-letters jobs left in the processing queue by workers
+// whose lease expired. An entry must stay lease-less for a full LEASE_TTL before it is
+// reclaimed, which covers the gap between a worker popping a job and taking its lease. It
+// returns how many entries it reclaimed and, like maintain, only db.ErrStaleLeader.
+func (s *JobServer) reclaimOrphanedJobs(ctx context.Context, epoch int64) (int, error) {
+	refs, err := s.queueMgr.OrphanedJobs(ctx)
+	if err != nil {
+		log.Printf("Leader reclaim scan error: %v", err)
+		return 0, nil
+	}
+	reclaimed := 0
+
+	now := time.Now()
+	seen := make(map[queue.JobRef]time.Time, len(refs))
+	for _, ref := range refs {
+		if _, dup := seen[ref]; dup {
+			continue
+		}
+		since, ok := s.orphanSince[ref]
+		if !ok {
+			since = now
+		}
+		if now.Sub(since) < queue.LEASE_TTL {
+			seen[ref] = since
+			continue
+		}
+		if err := s.reclaimJob(ctx, ref.Queue, ref.ID, epoch); errors.Is(err, db.ErrStaleLeader) {
+			return reclaimed, err
+		} else if err != nil {
+			log.Printf("Leader reclaim error for %s: %v", ref.ID, err)
+			seen[ref] = since
+			continue
+		}
+		reclaimed++
+	}
+	s.orphanSince = seen
+	return reclaimed, nil
+}
+
+// reclaimJob reconciles one orphaned processing entry against its task row.
+func (s *JobServer) reclaimJob(ctx context.Context, q, id string, epoch int64) error {
+	job, err := s.dbMgr.GetJob(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Leader reclaim: dropping unknown job %s from processing", id)
+		return s.queueMgr.AckProcessing(ctx, q, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch job.Status {
+	case "PENDING":
+		// Popped but never started: hand it back without spending a retry
+		log.Printf("Leader reclaim: requeueing unstarted job %s", id)
+		return s.queueMgr.RequeueFromProcessing(ctx, q, id)
+	case "RUNNING":
+		output := job.Output.String + "\n[auto] worker lease expired"
+		status, retries, max, err := s.dbMgr.ReclaimRunningJob(id, output, epoch)
+		switch {
+		case err != nil:
+			return err
+		case status == "":
+			// Finished meanwhile; the next scan sees the new status
+			return nil
+		case status == "PENDING":
+			log.Printf("Leader reclaim: requeueing job %s (retry %d/%d)", id, retries, max)
+			_ = s.queueMgr.PublishJobStatus(ctx, id, "PENDING", false)
+			return s.queueMgr.RequeueFromProcessing(ctx, q, id)
+		}
+		log.Printf("Leader reclaim: moving job %s to DLQ after %d retries", id, retries-1)
+		_ = s.queueMgr.PublishJobStatus(ctx, id, "FAILED", true)
+		return s.queueMgr.MoveToDLQ(ctx, q, id)
+	default:
+		// The worker recorded a final status but died before acking
+		log.Printf("Leader reclaim: removing finished job %s (%s) from processing", id, job.Status)
+		return s.queueMgr.AckProcessing(ctx, q, id)
 	}
 }
 
@@ -103,43 +395,91 @@ func (s *JobServer) SubmitJob(ctx context.Context, job *pb.Job) (*pb.JobResponse
 		}, errors.New("job command cannot be empty")
 	}
 
+	// Validate schedule before touching the database
+	record, err := scheduleRecord(job, time.Now())
+	if err != nil {
+		log.Printf("Received invalid job schedule: %v", err)
+		return &pb.JobResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+	if err := applyRetryPolicy(record, job.RetryPolicy); err != nil {
+		log.Printf("Received invalid retry policy: %v", err)
+		return &pb.JobResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	if len(job.IdempotencyKey) > maxIdempotencyKeyLen {
+		err := fmt.Errorf("idempotency key is longer than %d bytes", maxIdempotencyKeyLen)
+		log.Printf("Received invalid idempotency key: %v", err)
+		return &pb.JobResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
 	// Generate unique ID if not provided
 	if job.Id == "" {
 		job.Id = uuid.New().String()
 	}
 	job.CreatedAt = time.Now().Unix()
+	record.ID = job.Id
+	record.Command = job.Command
+	record.Priority = job.Priority
+	if job.IdempotencyKey != "" {
+		record.IdempotencyKey = sql.NullString{String: job.IdempotencyKey, Valid: true}
+	}
 
 	log.Printf("Processing job submission - ID: %s, Command: %s", job.Id, job.Command)
 
-	// Store job in database
-	if err := s.dbMgr.CreateJob(job.Id, job.Command); err != nil {
+	// Store job in database; a known idempotency key returns the original job instead
+	existing, err := s.dbMgr.CreateJob(record, time.Now().Add(-s.idempotencyWindow).Unix())
+	if err != nil {
 		log.Printf("Failed to create job %s in database: %v", job.Id, err)
+		msg := "Failed to create job in database"
+		if errors.Is(err, db.ErrJobExists) {
+			msg = fmt.Sprintf("Job ID %s already exists", job.Id)
+		}
 		return &pb.JobResponse{
 			Success: false,
-			Message: "Failed to create job in database",
+			Message: msg,
 		}, err
 	}
+	if existing != nil {
+		log.Printf("Job submission with idempotency key %q matches job %s", job.IdempotencyKey, existing.ID)
+		return &pb.JobResponse{
+			JobId:     existing.ID,
+			Success:   true,
+			Message:   "Job already submitted",
+			Status:    existing.Status,
+			Duplicate: true,
+		}, nil
+	}
 	log.Printf("Job %s stored in database successfully", job.Id)
 
-	// Push to queue - if this fails, we have a problem since job is already in DB
-	if err := s.queueMgr.PushJob(ctx, job.Id); err != nil {
-		log.Printf("Failed to push job %s to Redis queue: %v", job.Id, err)
-		// Try to mark the job as failed since it's in DB but not in queue
-		if updateErr := s.dbMgr.UpdateJobStatus(job.Id, "FAILED", "Failed to add job to processing queue"); updateErr != nil {
-			log.Printf("Also failed to update job status in DB: %v", updateErr)
-		}
+	// Future and recurring jobs are enqueued by the leader once due
+	if record.CronExpr.Valid || record.ExecuteAt.Valid {
+		log.Printf("Job %s scheduled for later execution", job.Id)
 		return &pb.JobResponse{
 			JobId:   job.Id,
-			Success: false,
-			Message: "Failed to queue job for processing",
-		}, err
+			Success: true,
+			Message: "Job scheduled successfully",
+			Status:  "PENDING",
+		}, nil
 	}
+
+	// The outbox entry written with the job is pushed to Redis by the relay
+	s.kickOutbox()
 	log.Printf("Job %s queued for processing", job.Id)
 
 	return &pb.JobResponse{
 		JobId:   job.Id,
 		Success: true,
 		Message: "Job submitted successfully",
+		Status:  "PENDING",
 	}, nil
 }
 
@@ -152,7 +492,156 @@ func (s *JobServer) GetJobStatus(ctx context.Context, jobId *pb.JobId) (*pb.JobS
 
 	job, err := s.dbMgr.GetJob(jobId.Id)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Job %s not found in database", jobId.Id)
+			return nil, status.Error(codes.NotFound, "job not found")
+		}
+		log.Printf("Error retrieving job %s from database: %v", jobId.Id, err)
+		return nil, err
+	}
+
+	log.Printf("Retrieved status for job %s: %s", jobId.Id, job.Status)
+	return toJobStatus(job), nil
+}
+
+func (s *JobServer) GetJobHistory(ctx context.Context, jobId *pb.JobId) (*pb.JobHistory, error) {
+	if strings.TrimSpace(jobId.Id) == "" {
+		log.Printf("Received empty job ID in history request")
+		return nil, errors.New("job ID cannot be empty")
+	}
+
+	if _, err := s.dbMgr.GetJob(jobId.Id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Job %s not found in database", jobId.Id)
+			return nil, status.Error(codes.NotFound, "job not found")
+		}
+		log.Printf("Error retrieving job %s from database: %v", jobId.Id, err)
+		return nil, err
+	}
+	attempts, err := s.dbMgr.GetJobHistory(jobId.Id)
+	if err != nil {
+		log.Printf("Error retrieving history of job %s: %v", jobId.Id, err)
+		return nil, err
+	}
+
+	now := time.Now()
+	history := &pb.JobHistory{JobId: jobId.Id}
+	for _, a := range attempts {
+		end := now
+		attempt := &pb.JobAttempt{
+			Attempt:   a.Attempt,
+			WorkerId:  a.WorkerID,
+			Host:      a.Host,
+			Status:    a.Status,
+			StartedAt: a.StartedAt.Unix(),
+			Output:    a.Output,
+			Result:    toExecutionResult(a.Result),
+		}
+		if a.FinishedAt.Valid {
+			end = a.FinishedAt.Time
+			attempt.FinishedAt = end.Unix()
+		}
+		attempt.DurationMs = end.Sub(a.StartedAt).Milliseconds()
+		history.Attempts = append(history.Attempts, attempt)
+	}
+	log.Printf("Retrieved %d attempts of job %s", len(history.Attempts), jobId.Id)
+	return history, nil
+}
+
+func (s *JobServer) ListJobs(ctx context.Context, req *pb.ListJobsRequest) (*pb.ListJobsResponse, error) {
+	filter := db.JobFilter{
+		CreatedAfter:    req.CreatedAfter,
+		CreatedBefore:   req.CreatedBefore,
+		UpdatedAfter:    req.UpdatedAfter,
+		UpdatedBefore:   req.UpdatedBefore,
+		CommandContains: req.CommandContains,
+		Queue:           req.Queue,
+		OrderBy:         req.OrderBy,
+		Ascending:       req.Ascending,
+		Limit:           int(req.PageSize),
+	}
+
+	for _, st := range req.Statuses {
+		st = strings.ToUpper(strings.TrimSpace(st))
+		if !validStatuses[st] {
+			return nil, fmt.Errorf("unknown job status %q", st)
+		}
+		filter.Statuses = append(filter.Statuses, st)
+	}
+
+	switch req.Kind {
+	case "":
+	case "cron":
+		cron := true
+		filter.Cron = &cron
+	case "oneshot":
+		cron := false
+		filter.Cron = &cron
+	default:
+		return nil, fmt.Errorf("unknown job kind %q (want cron or oneshot)", req.Kind)
+	}
+
+	switch filter.OrderBy {
+	case "":
+		filter.OrderBy = "created_at"
+	case "created_at", "updated_at":
+	default:
+		return nil, fmt.Errorf("cannot order by %q (want created_at or updated_at)", req.OrderBy)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	} else if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+
+	if req.PageToken != "" {
+		cursor, err := decodePageToken(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.OrderBy != filter.OrderBy || cursor.Ascending != filter.Ascending {
+			return nil, errors.New("page token does not match the requested ordering")
+		}
+		filter.AfterValue = cursor.Value
+		filter.AfterID = cursor.ID
+	}
+
+	// Fetch one extra row to learn whether another page exists
+	pageSize := filter.Limit
+	filter.Limit++
+	jobs, err := s.dbMgr.ListJobs(filter)
+	if err != nil {
+		log.Printf("Error listing jobs: %v", err)
+		return nil, err
+	}
+
+	resp := &pb.ListJobsResponse{}
+	if len(jobs) > pageSize {
+		jobs = jobs[:pageSize]
+		last := jobs[len(jobs)-1]
+		cursor := pageCursor{OrderBy: filter.OrderBy, Ascending: filter.Ascending, ID: last.ID, Value: last.CreatedAt}
+		if filter.OrderBy == "updated_at" {
+			cursor.Value = last.UpdatedAt
+		}
+		resp.NextPageToken = encodePageToken(cursor)
+	}
+	for _, job := range jobs {
+		resp.Jobs = append(resp.Jobs, toJobStatus(job))
+	}
+	log.Printf("Listed %d jobs", len(resp.Jobs))
+	return resp, nil
+}
+
+func (s *JobServer) CancelJob(ctx context.Context, jobId *pb.JobId) (*pb.JobResponse, error) {
+	if strings.TrimSpace(jobId.Id) == "" {
+		log.Printf("Received empty job ID in cancel request")
+		return nil, errors.New("job ID cannot be empty")
+	}
+
+	job, err := s.dbMgr.GetJob(jobId.Id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
 			log.Printf("Job %s not found in database", jobId.Id)
 			return nil, errors.New("job not found")
 		}
@@ -160,22 +649,218 @@ func (s *JobServer) GetJobStatus(ctx context.Context, jobId *pb.JobId) (*pb.JobS
 		return nil, err
 	}
 
-	// Handle nullable output properly
-	output := ""
-	if job.Output.Valid {
-		output = job.Output.String
+	// Not started yet (or a cron job between runs): cancel in the database and drop it from the queue
+	cancelled, err := s.dbMgr.CancelJob(job.ID)
+	if err != nil {
+		log.Printf("Failed to cancel job %s: %v", job.ID, err)
+		return nil, err
+	}
+	if cancelled {
+		if err := s.queueMgr.RemovePending(ctx, job.Queue, job.ID); err != nil {
+			// Workers skip cancelled jobs they pop, so a stale queue entry is harmless
+			log.Printf("Failed to remove cancelled job %s from queue: %v", job.ID, err)
+		}
+		if err := s.queueMgr.PublishJobStatus(ctx, job.ID, "CANCELLED", true); err != nil {
+			log.Printf("Failed to publish cancellation of job %s: %v", job.ID, err)
+		}
+		if n, err := s.dbMgr.SkipDependents(job.ID); err != nil {
+			log.Printf("Failed to skip dependents of cancelled job %s: %v", job.ID, err)
+		} else if n > 0 {
+			log.Printf("Skipped %d jobs depending on cancelled job %s", n, job.ID)
+		}
+		log.Printf("Job %s cancelled", job.ID)
+		return &pb.JobResponse{
+			JobId:   job.ID,
+			Success: true,
+			Message: "Job cancelled",
+		}, nil
 	}
 
-	log.Printf("Retrieved status for job %s: %s", jobId.Id, job.Status)
-	return &pb.JobStatus{
-		Id:        job.ID,
-		Status:    job.Status,
-		Output:    output,
-		CreatedAt: job.CreatedAt,
-		UpdatedAt: job.UpdatedAt,
+	// Re-read: the job may have been claimed by a worker since the first lookup
+	if job, err = s.dbMgr.GetJob(job.ID); err != nil {
+		return nil, err
+	}
+	if job.Status != "RUNNING" {
+		return &pb.JobResponse{
+			JobId:   job.ID,
+			Success: false,
+			Message: fmt.Sprintf("Job already finished with status %s", job.Status),
+		}, nil
+	}
+
+	// Running: ask the owning worker to kill it; the worker records the outcome
+	if err := s.queueMgr.PublishCancel(ctx, job.ID); err != nil {
+		log.Printf("Failed to publish cancellation for job %s: %v", job.ID, err)
+		return &pb.JobResponse{
+			JobId:   job.ID,
+			Success: false,
+			Message: "Failed to signal worker",
+		}, err
+	}
+	log.Printf("Cancellation requested for running job %s", job.ID)
+	return &pb.JobResponse{
+		JobId:   job.ID,
+		Success: true,
+		Message: "Cancellation requested",
 	}, nil
 }
 
+// toJobStatus converts a task row into its API representation.
+func toJobStatus(job *db.Job) *pb.JobStatus {
+	// Recurring jobs report next_run_at, delayed one-shot jobs their execute_at
+	var nextRunAt int64
+	if job.NextRunAt.Valid {
+		nextRunAt = job.NextRunAt.Time.Unix()
+	} else if job.ExecuteAt.Valid {
+		nextRunAt = job.ExecuteAt.Time.Unix()
+	}
+
+	status := &pb.JobStatus{
+		Id:         job.ID,
+		Status:     job.Status,
+		Output:     job.Output.String,
+		CreatedAt:  job.CreatedAt,
+		UpdatedAt:  job.UpdatedAt,
+		NextRunAt:  nextRunAt,
+		CronExpr:   job.CronExpr.String,
+		Priority:   job.Priority,
+		Queue:      job.Queue,
+		WorkflowId: job.WorkflowID.String,
+		Result:     toExecutionResult(job.Result),
+	}
+	return status
+}
+
+// toExecutionResult converts an attempt's result into its API representation; nil stays nil.
+func toExecutionResult(r *db.ExecutionResult) *pb.ExecutionResult {
+	if r == nil {
+		return nil
+	}
+	return &pb.ExecutionResult{
+		Stdout:      r.Stdout,
+		Stderr:      r.Stderr,
+		ExitCode:    r.ExitCode,
+		Signal:      r.Signal,
+		WallTimeMs:  r.WallTimeMs,
+		UserCpuMs:   r.UserCPUMs,
+		SystemCpuMs: r.SystemCPUMs,
+		MaxRssKb:    r.MaxRSSKB,
+	}
+}
+
+// scheduleRecord validates the scheduling fields of job and returns a db.Job carrying
+// execute_at, cron_expr, timezone, the first next_run_at, the execution timeout and the queue.
+// Schedule times at or before now leave execute_at unset so the job is queued immediately.
+func scheduleRecord(job *pb.Job, now time.Time) (*db.Job, error) {
+	record := &db.Job{}
+	if job.Timezone != "" && job.CronExpr == "" {
+		return nil, errors.New("timezone requires a cron expression")
+	}
+	if job.ScheduleTime < 0 {
+		return nil, errors.New("schedule time cannot be negative")
+	}
+	if job.TimeoutSeconds < 0 || job.TimeoutSeconds > math.MaxInt32 {
+		return nil, errors.New("timeout must be between 0 and 2147483647 seconds")
+	}
+	record.TimeoutSeconds = int32(job.TimeoutSeconds)
+	record.Queue = queue.DEFAULT_QUEUE
+	if job.Queue != "" {
+		if err := queue.ValidateQueueName(job.Queue); err != nil {
+			return nil, err
+		}
+		record.Queue = job.Queue
+	}
+
+	start := now
+	if job.ScheduleTime > now.Unix() {
+		start = time.Unix(job.ScheduleTime, 0)
+	}
+
+	if strings.TrimSpace(job.CronExpr) == "" {
+		if start.After(now) {
+			record.ExecuteAt = sql.NullTime{Time: start, Valid: true}
+		}
+		return record, nil
+	}
+
+	// For recurring jobs schedule_time only delays the first run
+	next, err := nextCronRun(job.CronExpr, job.Timezone, start)
+	if err != nil {
+		return nil, err
+	}
+	record.CronExpr = sql.NullString{String: strings.TrimSpace(job.CronExpr), Valid: true}
+	if job.Timezone != "" {
+		record.Timezone = sql.NullString{String: job.Timezone, Valid: true}
+	}
+	record.NextRunAt = sql.NullTime{Time: next, Valid: true}
+	return record, nil
+}
+
+// applyRetryPolicy validates policy and stores it on record, filling in defaults for fields
+// left at zero. A nil policy means all defaults.
+func applyRetryPolicy(record *db.Job, policy *pb.RetryPolicy) error {
+	if policy == nil {
+		policy = &pb.RetryPolicy{}
+	}
+	attempts := policy.MaxAttempts
+	if attempts == 0 {
+		attempts = defaultMaxAttempts
+	}
+	if attempts < 1 {
+		return errors.New("max attempts must be at least 1")
+	}
+	initial := policy.InitialBackoffSeconds
+	if initial == 0 {
+		initial = defaultBackoffSeconds
+	}
+	maxBackoff := policy.MaxBackoffSeconds
+	if maxBackoff == 0 {
+		maxBackoff = defaultMaxBackoffSeconds
+	}
+	if initial < 0 || maxBackoff < 0 || initial > math.MaxInt32 || maxBackoff > math.MaxInt32 {
+		return errors.New("backoff must be between 0 and 2147483647 seconds")
+	}
+	if initial > maxBackoff {
+		return errors.New("initial backoff cannot exceed max backoff")
+	}
+	multiplier := policy.BackoffMultiplier
+	if multiplier == 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+	if multiplier < 1 || math.IsInf(multiplier, 0) || math.IsNaN(multiplier) {
+		return errors.New("backoff multiplier must be at least 1")
+	}
+
+	record.MaxRetries = attempts - 1
+	record.RetryBackoffSeconds = int32(initial)
+	record.RetryMultiplier = multiplier
+	record.RetryMaxBackoffSeconds = int32(maxBackoff)
+	record.RetryExitCodes = policy.RetryableExitCodes
+	return nil
+}
+
+// nextCronRun returns the first activation of expr strictly after the given time,
+// evaluated in timezone (server local time when empty).
+func nextCronRun(expr, timezone string, after time.Time) (time.Time, error) {
+	loc := time.Local
+	if timezone != "" {
+		l, err := time.LoadLocation(timezone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timezone %q: %v", timezone, err)
+		}
+		loc = l
+	}
+	sched, err := cronParser.Parse(strings.TrimSpace(expr))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron expression %q: %v", expr, err)
+	}
+	next := sched.Next(after.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron expression %q never fires", expr)
+	}
+	return next, nil
+}
+
 func (s *JobServer) Close() error {
 	log.Print("Shutting down job server...")
 	var dbErr, queueErr error
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"distributed-task-scheduler/internal/db"
+	"distributed-task-scheduler/internal/queue"
+	pb "distributed-task-scheduler/proto"
+)
+
+// streamPollInterval bounds how long a stream waits for events before re-reading the job
+// from the database, which catches changes made without an event (e.g. leader sweeps).
+const streamPollInterval = 5 * time.Second
+
+// retrySettleDelay is how long a FAILED or TIMED_OUT job must stay unchanged before a
+// stream treats it as settled without a final event, as a worker may still schedule a retry.
+const retrySettleDelay = 60 * time.Second
+
+func (s *JobServer) WatchJob(req *pb.JobId, stream pb.JobService_WatchJobServer) error {
+	ctx := stream.Context()
+	if strings.TrimSpace(req.Id) == "" {
+		return errors.New("job ID cannot be empty")
+	}
+
+	// Take the cursor before reading the job so no change between the two is missed
+	cursor, err := s.queueMgr.LastJobEventID(ctx, req.Id)
+	if err != nil {
+		log.Printf("Error reading event stream of job %s: %v", req.Id, err)
+		return err
+	}
+	job, err := s.lookupJob(req.Id)
+	if err != nil {
+		return err
+	}
+	last := toJobStatus(job)
+	if err := stream.Send(last); err != nil {
+		return err
+	}
+	if jobSettled(job, time.Now()) {
+		return nil
+	}
+
+	log.Printf("Watching job %s", req.Id)
+	for {
+		events, err := s.queueMgr.ReadJobEvents(ctx, req.Id, cursor, streamPollInterval)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Printf("Error reading event stream of job %s: %v", req.Id, err)
+			return err
+		}
+		changed, final := len(events) == 0, false
+		for _, ev := range events {
+			cursor = ev.ID
+			if ev.Kind == queue.EVENT_STATUS {
+				changed = true
+				final = final || ev.Final
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		if job, err = s.dbMgr.GetJob(req.Id); err != nil {
+			return err
+		}
+		st := toJobStatus(job)
+		if st.Status != last.Status || st.UpdatedAt != last.UpdatedAt {
+			if err := stream.Send(st); err != nil {
+				return err
+			}
+			last = st
+		}
+		if final || jobSettled(job, time.Now()) {
+			return nil
+		}
+	}
+}
+
+func (s *JobServer) TailJobLogs(req *pb.TailJobLogsRequest, stream pb.JobService_TailJobLogsServer) error {
+	ctx := stream.Context()
+	if strings.TrimSpace(req.JobId) == "" {
+		return errors.New("job ID cannot be empty")
+	}
+	job, err := s.lookupJob(req.JobId)
+	if err != nil {
+		return err
+	}
+
+	cursor, sent := "0", false
+	block := time.Duration(0) // drain what is already there before waiting
+	for {
+		events, err := s.queueMgr.ReadJobEvents(ctx, req.JobId, cursor, block)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Printf("Error reading event stream of job %s: %v", req.JobId, err)
+			return err
+		}
+		done := false
+		for _, ev := range events {
+			cursor = ev.ID
+			switch ev.Kind {
+			case queue.EVENT_STDOUT, queue.EVENT_STDERR:
+				chunk := &pb.LogChunk{Stream: ev.Kind, Data: []byte(ev.Data), TimestampMs: ev.Time.UnixMilli()}
+				if err := stream.Send(chunk); err != nil {
+					return err
+				}
+				sent = true
+			case queue.EVENT_STATUS:
+				// A new attempt starts a new log; a final status ends it
+				done = ev.Final
+			}
+		}
+		if len(events) == queue.JOB_EVENTS_BATCH {
+			continue
+		}
+		if !req.Follow || done {
+			break
+		}
+		if len(events) == 0 && block > 0 {
+			// Quiet for a while: stop if the job settled without a final event
+			if job, err = s.dbMgr.GetJob(req.JobId); err != nil {
+				return err
+			}
+			if jobSettled(job, time.Now()) {
+				break
+			}
+		}
+		block = streamPollInterval
+	}
+
+	// The live log expires after a day; fall back to the stored output
+	if !sent && job.Output.Valid && jobSettled(job, time.Now()) {
+		return stream.Send(&pb.LogChunk{Stream: "output", Data: []byte(job.Output.String), TimestampMs: job.UpdatedAt * 1000})
+	}
+	return nil
+}
+
+// lookupJob loads a job for a streaming RPC, mapping a missing row to "job not found".
+func (s *JobServer) lookupJob(id string) (*db.Job, error) {
+	job, err := s.dbMgr.GetJob(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Job %s not found in database", id)
+			return nil, errors.New("job not found")
+		}
+		log.Printf("Error retrieving job %s from database: %v", id, err)
+		return nil, err
+	}
+	return job, nil
+}
+
+// jobSettled reports whether job is in a status it will not leave on its own. A failed run
+// may still be retried, so FAILED and TIMED_OUT only count once left alone for a while.
+func jobSettled(job *db.Job, now time.Time) bool {
+	switch job.Status {
+	case "SUCCEEDED", "CANCELLED", "SKIPPED":
+		return true
+	case "FAILED", "TIMED_OUT":
+		return now.Sub(time.Unix(job.UpdatedAt, 0)) > retrySettleDelay
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"distributed-task-scheduler/internal/queue"
+	pb "distributed-task-scheduler/proto"
+)
+
+// ListWorkers returns the workers registered in the queue backend. Workers whose lease
+// expired are only listed with include_dead, until the registry forgets them.
+func (c *ClusterServer) ListWorkers(ctx context.Context, req *pb.ListWorkersRequest) (*pb.ListWorkersResponse, error) {
+	workers, err := c.jobs.queueMgr.Workers(ctx)
+	if err != nil {
+		log.Printf("Error listing workers: %v", err)
+		return nil, err
+	}
+	resp := &pb.ListWorkersResponse{}
+	for _, w := range workers {
+		if !w.Alive && !req.IncludeDead {
+			continue
+		}
+		resp.Workers = append(resp.Workers, toWorkerInfo(w))
+	}
+	return resp, nil
+}
+
+// DrainWorker asks a worker to stop taking jobs and to exit once its in-flight jobs
+// finished. ListWorkers shows the progress until the worker leaves the registry.
+func (c *ClusterServer) DrainWorker(ctx context.Context, req *pb.WorkerControlRequest) (*pb.WorkerControlResponse, error) {
+	return c.controlWorker(ctx, req.WorkerId, queue.WORKER_DRAINING)
+}
+
+// PauseWorker asks a worker to stop taking jobs until it is resumed.
+func (c *ClusterServer) PauseWorker(ctx context.Context, req *pb.WorkerControlRequest) (*pb.WorkerControlResponse, error) {
+	return c.controlWorker(ctx, req.WorkerId, queue.WORKER_PAUSED)
+}
+
+// ResumeWorker asks a paused worker, or a draining one that has not exited yet, to take
+// jobs again.
+func (c *ClusterServer) ResumeWorker(ctx context.Context, req *pb.WorkerControlRequest) (*pb.WorkerControlResponse, error) {
+	return c.controlWorker(ctx, req.WorkerId, queue.WORKER_RUNNING)
+}
+
+// controlWorker records control for a live worker in the registry. Workers poll for it,
+// so the change takes effect within a few seconds.
+func (c *ClusterServer) controlWorker(ctx context.Context, workerId, control string) (*pb.WorkerControlResponse, error) {
+	if workerId == "" {
+		return nil, errors.New("worker ID cannot be empty")
+	}
+	workers, err := c.jobs.queueMgr.Workers(ctx)
+	if err != nil {
+		log.Printf("Error listing workers: %v", err)
+		return nil, err
+	}
+	var target *queue.WorkerInfo
+	for _, w := range workers {
+		if w.ID == workerId {
+			target = w
+			break
+		}
+	}
+	if target == nil {
+		return nil, errors.New("worker not found")
+	}
+	if !target.Alive {
+		return nil, fmt.Errorf("worker %s is dead", workerId)
+	}
+	if err := c.jobs.queueMgr.SetWorkerControl(ctx, workerId, control); err != nil {
+		log.Printf("Error setting control of worker %s: %v", workerId, err)
+		return nil, err
+	}
+	log.Printf("Worker %s asked to switch from %s to %s", workerId, target.State, control)
+
+	var msg string
+	switch control {
+	case queue.WORKER_DRAINING:
+		msg = fmt.Sprintf("Drain requested, %d jobs in flight", len(target.Running))
+	case queue.WORKER_PAUSED:
+		msg = fmt.Sprintf("Pause requested, %d jobs in flight keep running", len(target.Running))
+	default:
+		msg = "Resume requested"
+	}
+	return &pb.WorkerControlResponse{Success: true, Message: msg, Worker: toWorkerInfo(target)}, nil
+}
+
+func toWorkerInfo(w *queue.WorkerInfo) *pb.WorkerInfo {
+	return &pb.WorkerInfo{
+		Id:          w.ID,
+		Host:        w.Host,
+		Pid:         int32(w.PID),
+		Version:     w.Version,
+		Queues:      w.Queues,
+		Slots:       int32(w.Slots),
+		RunningJobs: w.Running,
+		StartedAt:   w.StartedAt.Unix(),
+		LastSeen:    w.LastSeen.Unix(),
+		Alive:       w.Alive,
+		State:       w.State,
+	}
+}
@@ -0,0 +1,267 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"distributed-task-scheduler/internal/db"
+	pb "distributed-task-scheduler/proto"
+
+	"github.com/google/uuid"
+)
+
+// maxWorkflowNodes bounds the size of a single workflow.
+const maxWorkflowNodes = 1000
+
+// workflowSkipGrace is how long a failed dependency must stay FAILED or TIMED_OUT before
+// the leader skips its dependents, leaving time for the worker to schedule a retry.
+const workflowSkipGrace = 60
+
+func (s *JobServer) SubmitWorkflow(ctx context.Context, wf *pb.Workflow) (*pb.WorkflowResponse, error) {
+	jobs, deps, err := workflowRecords(wf, time.Now())
+	if err != nil {
+		log.Printf("Received invalid workflow: %v", err)
+		return &pb.WorkflowResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	if wf.Id == "" {
+		wf.Id = uuid.New().String()
+	}
+	resp := &pb.WorkflowResponse{WorkflowId: wf.Id, JobIds: make(map[string]string, len(jobs))}
+	for _, job := range jobs {
+		job.WorkflowID = sql.NullString{String: wf.Id, Valid: true}
+		resp.JobIds[job.WorkflowNode.String] = job.ID
+	}
+
+	log.Printf("Processing workflow submission - ID: %s, nodes: %d", wf.Id, len(jobs))
+	record := &db.Workflow{ID: wf.Id, Name: wf.Name, CreatedAt: time.Now().Unix()}
+	if err := s.dbMgr.CreateWorkflow(record, jobs, deps); err != nil {
+		log.Printf("Failed to create workflow %s in database: %v", wf.Id, err)
+		return &pb.WorkflowResponse{
+			WorkflowId: wf.Id,
+			Success:    false,
+			Message:    "Failed to create workflow in database",
+		}, err
+	}
+
+	// Nodes without dependencies were given outbox entries; the rest wait until released
+	s.kickOutbox()
+	log.Printf("Workflow %s queued for processing", wf.Id)
+
+	resp.Success = true
+	resp.Message = "Workflow submitted successfully"
+	return resp, nil
+}
+
+func (s *JobServer) GetWorkflowStatus(ctx context.Context, id *pb.WorkflowId) (*pb.WorkflowStatus, error) {
+	if strings.TrimSpace(id.Id) == "" {
+		log.Printf("Received empty workflow ID in status request")
+		return nil, errors.New("workflow ID cannot be empty")
+	}
+
+	wf, err := s.dbMgr.GetWorkflow(id.Id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Workflow %s not found in database", id.Id)
+			return nil, errors.New("workflow not found")
+		}
+		log.Printf("Error retrieving workflow %s from database: %v", id.Id, err)
+		return nil, err
+	}
+	jobs, err := s.dbMgr.WorkflowJobs(wf.ID)
+	if err != nil {
+		log.Printf("Error retrieving jobs of workflow %s: %v", wf.ID, err)
+		return nil, err
+	}
+	deps, err := s.dbMgr.WorkflowDependencies(wf.ID)
+	if err != nil {
+		log.Printf("Error retrieving dependencies of workflow %s: %v", wf.ID, err)
+		return nil, err
+	}
+
+	names := make(map[string]string, len(jobs))
+	for _, job := range jobs {
+		names[job.ID] = job.WorkflowNode.String
+	}
+	status := &pb.WorkflowStatus{Id: wf.ID, Name: wf.Name, CreatedAt: wf.CreatedAt, UpdatedAt: wf.CreatedAt}
+	statuses := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		node := &pb.WorkflowNodeStatus{
+			Name:      job.WorkflowNode.String,
+			JobId:     job.ID,
+			Status:    job.Status,
+			UpdatedAt: job.UpdatedAt,
+		}
+		for _, parent := range deps[job.ID] {
+			node.DependsOn = append(node.DependsOn, names[parent])
+		}
+		status.Nodes = append(status.Nodes, node)
+		statuses = append(statuses, job.Status)
+		if job.UpdatedAt > status.UpdatedAt {
+			status.UpdatedAt = job.UpdatedAt
+		}
+	}
+	status.Status = workflowState(statuses)
+	log.Printf("Retrieved status for workflow %s: %s", wf.ID, status.Status)
+	return status, nil
+}
+
+// workflowState summarises node statuses: RUNNING while any node can still run, then
+// SUCCEEDED if every node succeeded, FAILED if any failed or timed out, else CANCELLED.
+func workflowState(statuses []string) string {
+	failed, cancelled := false, false
+	for _, st := range statuses {
+		switch st {
+		case "PENDING", "RUNNING", "BLOCKED":
+			return "RUNNING"
+		case "FAILED", "TIMED_OUT":
+			failed = true
+		case "CANCELLED":
+			cancelled = true
+		}
+	}
+	switch {
+	case failed:
+		return "FAILED"
+	case cancelled:
+		return "CANCELLED"
+	default:
+		return "SUCCEEDED"
+	}
+}
+
+// workflowRecords validates wf and returns its task rows plus the dependency edges keyed by
+// task ID. Node jobs go through the same checks as SubmitJob but cannot be scheduled.
+func workflowRecords(wf *pb.Workflow, now time.Time) ([]*db.Job, map[string][]string, error) {
+	if len(wf.Nodes) == 0 {
+		return nil, nil, errors.New("workflow has no nodes")
+	}
+	if len(wf.Nodes) > maxWorkflowNodes {
+		return nil, nil, fmt.Errorf("workflow has %d nodes, at most %d are allowed", len(wf.Nodes), maxWorkflowNodes)
+	}
+
+	ids := make(map[string]string, len(wf.Nodes))
+	jobs := make([]*db.Job, 0, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		if strings.TrimSpace(node.Name) == "" {
+			return nil, nil, errors.New("workflow node name cannot be empty")
+		}
+		if _, dup := ids[node.Name]; dup {
+			return nil, nil, fmt.Errorf("duplicate workflow node %q", node.Name)
+		}
+		job := node.Job
+		if job == nil || strings.TrimSpace(job.Command) == "" {
+			return nil, nil, fmt.Errorf("node %q: job command cannot be empty", node.Name)
+		}
+		if job.CronExpr != "" || job.ScheduleTime != 0 {
+			return nil, nil, fmt.Errorf("node %q: workflow jobs cannot be scheduled", node.Name)
+		}
+		record, err := scheduleRecord(job, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("node %q: %v", node.Name, err)
+		}
+		if err := applyRetryPolicy(record, job.RetryPolicy); err != nil {
+			return nil, nil, fmt.Errorf("node %q: %v", node.Name, err)
+		}
+		record.ID = job.Id
+		if record.ID == "" {
+			record.ID = uuid.New().String()
+		}
+		record.Command = job.Command
+		record.Priority = job.Priority
+		record.WorkflowNode = sql.NullString{String: node.Name, Valid: true}
+		ids[node.Name] = record.ID
+		jobs = append(jobs, record)
+	}
+
+	deps := make(map[string][]string)
+	parents := make(map[string][]string, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		seen := make(map[string]bool)
+		for _, dep := range node.DependsOn {
+			if _, ok := ids[dep]; !ok {
+				return nil, nil, fmt.Errorf("node %q depends on unknown node %q", node.Name, dep)
+			}
+			if dep == node.Name {
+				return nil, nil, fmt.Errorf("node %q depends on itself", node.Name)
+			}
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			parents[node.Name] = append(parents[node.Name], dep)
+			deps[ids[node.Name]] = append(deps[ids[node.Name]], ids[dep])
+		}
+	}
+	if cycle := findCycle(wf.Nodes, parents); cycle != "" {
+		return nil, nil, fmt.Errorf("workflow has a dependency cycle through node %q", cycle)
+	}
+	return jobs, deps, nil
+}
+
+// findCycle returns the name of a node on a dependency cycle, or "" if the graph is acyclic.
+func findCycle(nodes []*pb.WorkflowNode, parents map[string][]string) string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(nodes))
+	var visit func(name string) string
+	visit = func(name string) string {
+		switch state[name] {
+		case visiting:
+			return name
+		case done:
+			return ""
+		}
+		state[name] = visiting
+		for _, p := range parents[name] {
+			if c := visit(p); c != "" {
+				return c
+			}
+		}
+		state[name] = done
+		return ""
+	}
+	for _, node := range nodes {
+		if c := visit(node.Name); c != "" {
+			return c
+		}
+	}
+	return ""
+}
+
+// releaseWorkflowJobs enqueues blocked workflow jobs whose dependencies have all succeeded
+// and skips those stranded behind a dependency that will not succeed. It returns how many
+// jobs it skipped and released. Only db.ErrStaleLeader is returned; other errors are logged.
+func (s *JobServer) releaseWorkflowJobs(epoch int64) (int64, int, error) {
+	skipped, err := s.dbMgr.SkipStrandedTasks(workflowSkipGrace, epoch)
+	if errors.Is(err, db.ErrStaleLeader) {
+		return 0, 0, err
+	} else if err != nil {
+		log.Printf("Leader workflow skip error: %v", err)
+	} else if skipped > 0 {
+		log.Printf("Leader workflow: skipped %d jobs behind failed dependencies", skipped)
+	}
+	ids, err := s.dbMgr.ReleaseReadyTasks(epoch)
+	if errors.Is(err, db.ErrStaleLeader) {
+		return skipped, 0, err
+	} else if err != nil {
+		log.Printf("Leader workflow release error: %v", err)
+		return skipped, 0, nil
+	}
+	if len(ids) > 0 {
+		log.Printf("Leader workflow: released %d jobs", len(ids))
+		s.kickOutbox()
+	}
+	return skipped, len(ids), nil
+}
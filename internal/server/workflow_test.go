@@ -0,0 +1,114 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	pb "distributed-task-scheduler/proto"
+)
+
+func node(name string, deps ...string) *pb.WorkflowNode {
+	return &pb.WorkflowNode{Name: name, Job: &pb.Job{Command: "echo " + name}, DependsOn: deps}
+}
+
+func TestWorkflowRecordsRejectsInvalidGraphs(t *testing.T) {
+	tooMany := make([]*pb.WorkflowNode, maxWorkflowNodes+1)
+	for i := range tooMany {
+		tooMany[i] = node(fmt.Sprint(i))
+	}
+	for _, tc := range []struct {
+		name  string
+		nodes []*pb.WorkflowNode
+		want  string
+	}{
+		{"empty", nil, "no nodes"},
+		{"too many nodes", tooMany, "at most"},
+		{"unnamed node", []*pb.WorkflowNode{node(" ")}, "name cannot be empty"},
+		{"duplicate node", []*pb.WorkflowNode{node("a"), node("a")}, `duplicate workflow node "a"`},
+		{"missing job", []*pb.WorkflowNode{{Name: "a"}}, "command cannot be empty"},
+		{"scheduled node", []*pb.WorkflowNode{{Name: "a", Job: &pb.Job{Command: "true", CronExpr: "* * * * *"}}}, "cannot be scheduled"},
+		{"invalid retry policy", []*pb.WorkflowNode{{Name: "a", Job: &pb.Job{Command: "true", RetryPolicy: &pb.RetryPolicy{MaxAttempts: -1}}}}, `node "a": max attempts`},
+		{"missing dependency", []*pb.WorkflowNode{node("a", "ghost")}, `unknown node "ghost"`},
+		{"self dependency", []*pb.WorkflowNode{node("a", "a")}, "depends on itself"},
+		{"two-node cycle", []*pb.WorkflowNode{node("a", "b"), node("b", "a")}, "dependency cycle"},
+		{"cycle behind a root", []*pb.WorkflowNode{node("root"), node("a", "root", "c"), node("b", "a"), node("c", "b")}, "dependency cycle"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := workflowRecords(&pb.Workflow{Nodes: tc.nodes}, time.Now())
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("workflowRecords = %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowRecordsEdges(t *testing.T) {
+	nodes := []*pb.WorkflowNode{node("a"), node("b", "a", "a"), node("c", "a", "b")}
+	nodes[0].Job.Id = "fixed"
+	jobs, deps, err := workflowRecords(&pb.Workflow{Nodes: nodes}, time.Now())
+	if err != nil {
+		t.Fatalf("workflowRecords: %v", err)
+	}
+	ids := map[string]string{}
+	for _, job := range jobs {
+		ids[job.WorkflowNode.String] = job.ID
+	}
+	if ids["a"] != "fixed" || ids["b"] == "" || ids["c"] == "" {
+		t.Fatalf("task IDs %v", ids)
+	}
+	if len(deps[ids["a"]]) != 0 {
+		t.Fatalf("root has parents %v", deps[ids["a"]])
+	}
+	if got := deps[ids["b"]]; len(got) != 1 || got[0] != ids["a"] {
+		t.Fatalf("b depends on %v, want only a (duplicates collapse)", got)
+	}
+	if got := deps[ids["c"]]; len(got) != 2 || got[0] != ids["a"] || got[1] != ids["b"] {
+		t.Fatalf("c depends on %v, want a and b", got)
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		parents map[string][]string
+		cyclic  bool
+	}{
+		{"no edges", nil, false},
+		{"diamond", map[string][]string{"b": {"a"}, "c": {"a"}, "d": {"b", "c"}}, false},
+		{"chain", map[string][]string{"b": {"a"}, "c": {"b"}, "d": {"c"}}, false},
+		{"loop", map[string][]string{"a": {"d"}, "b": {"a"}, "c": {"b"}, "d": {"c"}}, true},
+		{"loop off a diamond", map[string][]string{"b": {"a"}, "c": {"a", "d"}, "d": {"c"}}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes := []*pb.WorkflowNode{node("a"), node("b"), node("c"), node("d")}
+			got := findCycle(nodes, tc.parents)
+			if tc.cyclic == (got == "") {
+				t.Fatalf("findCycle = %q, want cyclic=%v", got, tc.cyclic)
+			}
+			if tc.cyclic {
+				// The reported node must lie on the cycle, i.e. reach itself
+				seen := map[string]bool{}
+				var reaches func(string) bool
+				reaches = func(n string) bool {
+					for _, p := range tc.parents[n] {
+						if p == got {
+							return true
+						}
+						if !seen[p] {
+							seen[p] = true
+							if reaches(p) {
+								return true
+							}
+						}
+					}
+					return false
+				}
+				if !reaches(got) {
+					t.Fatalf("findCycle = %q, which is not on a cycle", got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"log"
+
+	"distributed-task-scheduler/internal/queue"
+)
+
+// pollControl takes the run state asked for through the worker registry. No request means
+// queue.WORKER_RUNNING.
+func (w *Worker) pollControl() {
+	control, err := w.queueMgr.WorkerControl(context.Background(), w.id)
+	if err != nil {
+		log.Printf("Worker %s: failed to read control: %v", w.id, err)
+		return
+	}
+	switch control {
+	case "":
+		w.setState(queue.WORKER_RUNNING)
+	case queue.WORKER_RUNNING, queue.WORKER_PAUSED, queue.WORKER_DRAINING:
+		w.setState(control)
+	default:
+		log.Printf("Worker %s: ignoring unknown control %q", w.id, control)
+	}
+}
+
+func (w *Worker) setState(state string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.state == state {
+		return
+	}
+	log.Printf("Worker %s: %s -> %s with %d jobs in flight", w.id, w.state, state, len(w.running))
+	w.state = state
+	close(w.stateChanged)
+	w.stateChanged = make(chan struct{})
+}
+
+// accepting reports whether the worker takes new jobs. If it does not, the returned
+// channel is closed when its state changes next.
+func (w *Worker) accepting() (bool, <-chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state == queue.WORKER_RUNNING, w.stateChanged
+}
+
+// checkDrained closes drained once a draining worker has no jobs in flight.
+func (w *Worker) checkDrained() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.state != queue.WORKER_DRAINING || len(w.running) > 0 {
+		return
+	}
+	select {
+	case <-w.drained:
+	default:
+		close(w.drained)
+	}
+}
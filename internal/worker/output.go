@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"sync"
+
+	"distributed-task-scheduler/internal/queue"
+)
+
+// outputStreamer collects a command's combined output like CombinedOutput does, as well as
+// stdout and stderr on their own, and appends every chunk to the job's event stream so it
+// can be tailed while the job runs.
+type outputStreamer struct {
+	queueMgr queue.Events
+	jobId    string
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+	failed bool // shipping stopped after an error; output is still collected
+}
+
+func newOutputStreamer(queueMgr queue.Events, jobId string) *outputStreamer {
+	return &outputStreamer{queueMgr: queueMgr, jobId: jobId}
+}
+
+// writer returns the io.Writer for one of the command's streams (queue.EVENT_STDOUT or
+// queue.EVENT_STDERR).
+func (o *outputStreamer) writer(stream string) io.Writer {
+	w := &streamWriter{o: o, stream: stream, own: &o.stdout}
+	if stream == queue.EVENT_STDERR {
+		w.own = &o.stderr
+	}
+	return w
+}
+
+// Bytes returns everything written so far.
+func (o *outputStreamer) Bytes() []byte {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.Bytes()
+}
+
+// Streams returns stdout and stderr written so far.
+func (o *outputStreamer) Streams() (stdout, stderr string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.stdout.String(), o.stderr.String()
+}
+
+type streamWriter struct {
+	o      *outputStreamer
+	stream string
+	own    *bytes.Buffer // the buffer of this stream alone
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	o := w.o
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.buf.Write(p)
+	w.own.Write(p)
+	if !o.failed {
+		if err := o.queueMgr.AppendJobOutput(context.Background(), o.jobId, w.stream, p); err != nil {
+			log.Printf("Failed to stream output of job %s, continuing without live logs: %v", o.jobId, err)
+			o.failed = true
+		}
+	}
+	return len(p), nil
+}
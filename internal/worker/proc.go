@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+
+	"distributed-task-scheduler/internal/db"
+)
+
+// PROCESS_WAIT_DELAY bounds how long Wait blocks on output pipes after the process is killed.
+const PROCESS_WAIT_DELAY = 5 * time.Second
+
+// killGroupOnCancel starts cmd in its own process group and makes context cancellation
+// kill the whole group, so children spawned by `sh -c` do not outlive the job.
+func killGroupOnCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = PROCESS_WAIT_DELAY
+}
+
+// executionResult reads how cmd ended from its process state. The exit code is -1 when the
+// process was killed by a signal or never started; wall is how long the run took.
+func executionResult(cmd *exec.Cmd, wall time.Duration) *db.ExecutionResult {
+	res := &db.ExecutionResult{ExitCode: -1, WallTimeMs: wall.Milliseconds()}
+	state := cmd.ProcessState
+	if state == nil {
+		return res
+	}
+	res.ExitCode = int32(state.ExitCode())
+	res.UserCPUMs = state.UserTime().Milliseconds()
+	res.SystemCPUMs = state.SystemTime().Milliseconds()
+	if ws, ok := state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		res.Signal = ws.Signal().String()
+	}
+	if ru, ok := state.SysUsage().(*syscall.Rusage); ok {
+		res.MaxRSSKB = ru.Maxrss // kilobytes on Linux
+	}
+	return res
+}
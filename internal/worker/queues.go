@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"distributed-task-scheduler/internal/queue"
+)
+
+// MAX_QUEUE_WEIGHT bounds a queue weight, so the weights of a subscription list can be
+// summed without overflowing.
+const MAX_QUEUE_WEIGHT = 1000
+
+// QueueWeight is a queue subscription. Weights are relative: a queue with weight 3 is tried
+// first three times as often as one with weight 1 whenever both have work.
+type QueueWeight struct {
+	Name   string
+	Weight int
+}
+
+// ParseQueueWeights parses a subscription list such as "default:1,batch:3". A missing weight
+// defaults to 1; a weight must be an integer from 1 to MAX_QUEUE_WEIGHT.
+func ParseQueueWeights(spec string) ([]QueueWeight, error) {
+	var queues []QueueWeight
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, weightStr, hasWeight := strings.Cut(part, ":")
+		weight := 1
+		if hasWeight {
+			n, err := strconv.Atoi(weightStr)
+			if err != nil || n < 1 || n > MAX_QUEUE_WEIGHT {
+				return nil, fmt.Errorf("invalid weight %q for queue %s", weightStr, name)
+			}
+			weight = n
+		}
+		if err := queue.ValidateQueueName(name); err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("queue %s listed more than once", name)
+		}
+		seen[name] = true
+		queues = append(queues, QueueWeight{Name: name, Weight: weight})
+	}
+	if len(queues) == 0 {
+		return nil, fmt.Errorf("no queues in %q", spec)
+	}
+	return queues, nil
+}
+
+// pollOrder returns the subscribed queue names in the order the next pop should try them.
+// Each position is drawn at random in proportion to the remaining weights, so busy queues
+// with a low weight still get served, just less often.
+func pollOrder(queues []QueueWeight) []string {
+	remaining := append([]QueueWeight(nil), queues...)
+	total := 0
+	for _, q := range remaining {
+		total += q.Weight
+	}
+	order := make([]string, 0, len(queues))
+	for len(remaining) > 0 {
+		r := rand.IntN(total)
+		i := 0
+		for r >= remaining[i].Weight {
+			r -= remaining[i].Weight
+			i++
+		}
+		order = append(order, remaining[i].Name)
+		total -= remaining[i].Weight
+		remaining = append(remaining[:i], remaining[i+1:]...)
+	}
+	return order
+}
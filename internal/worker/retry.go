@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"distributed-task-scheduler/internal/db"
+)
+
+// RETRY_JITTER spreads each retry delay uniformly over +/- this fraction, so jobs that
+// failed together do not all retry at the same moment.
+const RETRY_JITTER = 0.2
+
+// retryDelay returns how long to wait before retry number attempt (1 for the first retry).
+func retryDelay(job *db.Job, attempt int32) time.Duration {
+	delay := float64(job.RetryBackoffSeconds) * math.Pow(job.RetryMultiplier, float64(attempt-1))
+	delay = math.Min(delay, float64(job.RetryMaxBackoffSeconds))
+	delay *= 1 + RETRY_JITTER*(2*rand.Float64()-1)
+	return time.Duration(delay * float64(time.Second))
+}
+
+// retryable reports whether a failed run may be retried under the job's policy. Timeouts
+// are always retryable; other failures only if their exit code is listed, when a list is set.
+func retryable(job *db.Job, status string, exitCode int32) bool {
+	if status == "TIMED_OUT" || len(job.RetryExitCodes) == 0 {
+		return true
+	}
+	return slices.Contains(job.RetryExitCodes, exitCode)
+}
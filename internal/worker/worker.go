@@ -2,9 +2,14 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"os/exec"
+	"slices"
+	"sort"
+	"sync"
 	"time"
 
 	"distributed-task-scheduler/internal/db"
@@ -12,54 +17,189 @@ import (
 )
 
 const (
-	RECONNECT_DELAY = 5 * time.Second
-	MAX_RETRIES     = 3
+	RECONNECT_DELAY    = 5 * time.Second
+	MAX_RETRIES        = 3
+	HEARTBEAT_INTERVAL = queue.LEASE_TTL / 3
+	// CONTROL_INTERVAL is how often a worker checks the registry for a pause, drain or
+	// resume request.
+	CONTROL_INTERVAL = 2 * time.Second
+	// MAX_DELIVERIES dead-letters a started job whose queue entry was handed out this often
+	// without finishing, on backends that count deliveries.
+	MAX_DELIVERIES = 5
+)
+
+// Version identifies the worker build in the worker registry. Release builds set it with
+// -ldflags "-X distributed-task-scheduler/internal/worker.Version=<version>".
+var Version = "dev"
+
+var (
+	// errCancelRequested is the cancel cause of a job stopped through CancelJob.
+	errCancelRequested = errors.New("job cancelled by request")
+	// errJobTimedOut is the cancel cause of a job that exceeded its timeout_seconds.
+	errJobTimedOut = errors.New("job timed out")
 )
 
 type Worker struct {
 	id        string
-	dbMgr     *db.DBManager
-	queueMgr  *queue.QueueManager
+	host      string // recorded with each attempt
+	dbMgr     db.Store
+	queueMgr  queue.Backend
 	redisAddr string
+	slots     int
+	queues    []QueueWeight
+	startedAt time.Time
+
+	// jobsCtx is the parent of every running job. Unlike the context passed to Start it
+	// is only cancelled by Kill, so stopping the worker lets in-flight jobs finish.
+	jobsCtx  context.Context
+	killJobs context.CancelFunc
+
+	mu      sync.Mutex
+	running map[string]context.CancelCauseFunc // jobs executing on this worker
+	// state is the run state asked for through the worker registry, e.g.
+	// queue.WORKER_PAUSED; stateChanged is closed and replaced whenever it changes.
+	state        string
+	stateChanged chan struct{}
+	drained      chan struct{} // closed once a drain finds no jobs in flight
 }
 
-func NewWorker(id string, dsn string, redisAddr string) (*Worker, error) {
-	log.Printf("Initializing worker %s with DB: %s, Redis: %s", id, dsn, redisAddr)
+// NewWorker creates a worker that runs up to slots jobs concurrently, taken from the given
+// queues in proportion to their weights. queueBackend selects where queues live (see queue.Open).
+func NewWorker(id string, dsn string, queueBackend string, redisAddr string, slots int, queues []QueueWeight) (*Worker, error) {
+	log.Printf("Initializing worker %s with DB: %s, queue backend: %s, Redis: %s, slots: %d, queues: %v", id, dsn, queueBackend, redisAddr, slots, queues)
+	if err := checkCapacity(slots, queues); err != nil {
+		return nil, err
+	}
 
-	dbMgr, err := db.NewDBManager(dsn)
+	dbMgr, err := db.NewDBManager(dsn, false)
 	if err != nil {
 		log.Printf("Failed to initialize database: %v", err)
 		return nil, fmt.Errorf("failed to initialize database: %v", err)
 	}
 
-	queueMgr, err := queue.NewQueueManager(redisAddr)
+	queueMgr, err := queue.Open(queueBackend, redisAddr, dsn)
 	if err != nil {
-		log.Printf("Failed to initialize Redis queue: %v", err)
+		log.Printf("Failed to initialize queue: %v", err)
 		dbMgr.Close() // Clean up database connection
-		return nil, fmt.Errorf("failed to initialize Redis queue: %v", err)
+		return nil, fmt.Errorf("failed to initialize queue: %v", err)
 	}
 
 	log.Printf("Worker %s initialized successfully", id)
+	w, _ := NewWorkerWith(id, dbMgr, queueMgr, slots, queues)
+	w.redisAddr = redisAddr
+	return w, nil
+}
+
+// NewWorkerWith creates a worker on an existing store and queue, e.g. the in-memory ones
+// of the standalone mode. Closing the worker closes both.
+func NewWorkerWith(id string, store db.Store, queueMgr queue.Backend, slots int, queues []QueueWeight) (*Worker, error) {
+	if err := checkCapacity(slots, queues); err != nil {
+		return nil, err
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		log.Printf("Worker %s: failed to read hostname: %v", id, err)
+	}
+	jobsCtx, killJobs := context.WithCancel(context.Background())
 	return &Worker{
-		id:        id,
-		dbMgr:     dbMgr,
-		queueMgr:  queueMgr,
-		redisAddr: redisAddr,
+		id:       id,
+		host:     host,
+		dbMgr:    store,
+		queueMgr: queueMgr,
+		slots:    slots,
+		queues:   queues,
+		jobsCtx:  jobsCtx,
+		killJobs: killJobs,
+		running:  make(map[string]context.CancelCauseFunc),
+
+		state:        queue.WORKER_RUNNING,
+		stateChanged: make(chan struct{}),
+		drained:      make(chan struct{}),
 	}, nil
 }
 
+func checkCapacity(slots int, queues []QueueWeight) error {
+	if slots < 1 {
+		return fmt.Errorf("worker needs at least one slot, got %d", slots)
+	}
+	if len(queues) == 0 {
+		return errors.New("worker needs at least one queue")
+	}
+	return nil
+}
+
+// Start runs the worker's slots until ctx is done, then waits for in-flight jobs to
+// finish before returning. Call Kill to stop those jobs instead of waiting. A drain
+// requested through the worker registry also stops the worker; Start then returns nil.
 func (w *Worker) Start(ctx context.Context) error {
-	log.Printf("Worker %s starting... Waiting for jobs", w.id)
+	log.Printf("Worker %s starting %d slots... Waiting for jobs", w.id, w.slots)
+
+	cancels, err := w.queueMgr.SubscribeCancellations(w.jobsCtx)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to cancellations: %v", err)
+	}
+	go w.watchCancellations(cancels)
+
+	w.startedAt = time.Now()
+	stopRegistry := make(chan struct{})
+	registryDone := make(chan struct{})
+	go func() {
+		w.advertise(stopRegistry)
+		close(registryDone)
+	}()
+
+	slotsCtx, stopSlots := context.WithCancel(ctx)
+	defer stopSlots()
+	var wg sync.WaitGroup
+	for slot := 0; slot < w.slots; slot++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.runSlot(slotsCtx)
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		if n := w.inFlight(); n > 0 {
+			log.Printf("Worker %s draining %d in-flight jobs...", w.id, n)
+		}
+	case <-w.drained:
+		log.Printf("Worker %s drained, stopping", w.id)
+		stopSlots()
+	}
+	wg.Wait()
+	close(stopRegistry)
+	<-registryDone
+	log.Printf("Worker %s shutting down...", w.id)
+	return ctx.Err()
+}
+
+// Kill cancels every running job, e.g. when a drain takes too long.
+func (w *Worker) Kill() {
+	log.Printf("Worker %s killing %d in-flight jobs", w.id, w.inFlight())
+	w.killJobs()
+}
 
+// runSlot processes jobs one at a time until ctx is done. It takes no jobs while the
+// worker is paused or draining.
+func (w *Worker) runSlot(ctx context.Context) {
 	for {
+		if ok, changed := w.accepting(); !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+				continue
+			}
+		}
 		select {
 		case <-ctx.Done():
-			log.Printf("Worker %s shutting down...", w.id)
-			return ctx.Err()
+			return
 		default:
 			if err := w.processNextJob(ctx); err != nil {
 				if err == context.Canceled || err == context.DeadlineExceeded {
-					return err
+					return
 				}
 
 				// Handle queue errors
@@ -72,7 +212,7 @@ func (w *Worker) Start(ctx context.Context) error {
 				// Add delay before retrying on error
 				select {
 				case <-ctx.Done():
-					return ctx.Err()
+					return
 				case <-time.After(RECONNECT_DELAY):
 					continue
 				}
@@ -83,18 +223,21 @@ func (w *Worker) Start(ctx context.Context) error {
 
 func (w *Worker) processNextJob(ctx context.Context) error {
 	// Get next job from queue with retries
-	var jobId string
+	var jobId, queueName string
 	var err error
 
 	for retries := 0; retries < MAX_RETRIES; retries++ {
 		log.Printf("Worker %s waiting for next job (attempt %d/%d)...", w.id, retries+1, MAX_RETRIES)
-		jobId, err = w.queueMgr.PopJob(ctx)
+		jobId, queueName, err = w.queueMgr.PopJob(ctx, pollOrder(w.queues))
 		if err == nil {
 			break
 		}
 		if err == queue.ErrQueueTimeout {
 			return err // Normal timeout, caller will continue
 		}
+		if ctx.Err() != nil {
+			return ctx.Err() // Stopping, don't retry
+		}
 		log.Printf("Worker %s: error getting job from queue (attempt %d/%d): %v", w.id, retries+1, MAX_RETRIES, err)
 		if retries < MAX_RETRIES-1 {
 			time.Sleep(RECONNECT_DELAY)
@@ -104,7 +247,37 @@ func (w *Worker) processNextJob(ctx context.Context) error {
 		return fmt.Errorf("failed to get job after %d attempts: %v", MAX_RETRIES, err)
 	}
 
-	log.Printf("Worker %s received job %s", w.id, jobId)
+	log.Printf("Worker %s received job %s from queue %s", w.id, jobId, queueName)
+
+	// The worker may have been paused or drained while waiting; leave the job to others
+	if ok, _ := w.accepting(); !ok {
+		log.Printf("Worker %s no longer takes jobs, returning %s to queue %s", w.id, jobId, queueName)
+		if err := w.queueMgr.RequeueFromProcessing(context.Background(), queueName, jobId); err != nil {
+			return fmt.Errorf("failed to return job %s to queue %s: %v", jobId, queueName, err)
+		}
+		return nil
+	}
+
+	// From here on the job runs under jobsCtx so a drain does not interrupt it
+	ctx = w.jobsCtx
+
+	// Hold a lease on the job for as long as we work on it, so the leader can tell a
+	// crashed worker's processing entry from a live one
+	if err := w.queueMgr.RenewLease(ctx, jobId, w.id); err != nil {
+		log.Printf("Worker %s: failed to take lease on %s: %v", w.id, jobId, err)
+	}
+	stopHeartbeat := make(chan struct{})
+	go w.heartbeat(jobId, stopHeartbeat)
+	duplicate := false // the job is running elsewhere, whose lease must be left alone
+	defer func() {
+		close(stopHeartbeat)
+		if duplicate {
+			return
+		}
+		if err := w.queueMgr.ReleaseLease(context.Background(), jobId); err != nil {
+			log.Printf("Worker %s: failed to release lease on %s: %v", w.id, jobId, err)
+		}
+	}()
 
 	// Get job details from database with retries
 	var job *db.Job
@@ -127,15 +300,67 @@ func (w *Worker) processNextJob(ctx context.Context) error {
 
 	log.Printf("Worker %s processing job %s: %s", w.id, jobId, job.Command)
 
-	// Update status to RUNNING
-	if err := w.dbMgr.UpdateJobStatus(jobId, "RUNNING", ""); err != nil {
+	// Per-job context so CancelJob can stop the command. It is tracked before the job is
+	// marked RUNNING so a cancellation sent right after the status change is not missed.
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	w.track(jobId, cancel)
+	defer func() {
+		w.untrack(jobId)
+		cancel(nil)
+	}()
+
+	// A redelivered entry was claimed from a worker that stopped heartbeating
+	if deliveries, err := w.queueMgr.Deliveries(ctx, queueName, jobId); err != nil {
+		log.Printf("Worker %s: failed to read deliveries of %s: %v", w.id, jobId, err)
+	} else if deliveries > 1 && w.recoverRedelivery(ctx, job, queueName, deliveries) {
+		return nil
+	}
+
+	// Update status to RUNNING; jobs cancelled while queued are dropped here
+	attempt, err := w.dbMgr.ClaimJob(jobId, w.id, w.host)
+	if err != nil {
 		log.Printf("Worker %s failed to update job %s to RUNNING: %v", w.id, jobId, err)
 		return fmt.Errorf("failed to update job status: %v", err)
 	}
+	if attempt == 0 {
+		// Queue entries are delivered at least once, so a job may arrive again while it
+		// runs on another worker; drop this copy without touching the other one's state
+		if job.Status == "RUNNING" {
+			duplicate = true
+			log.Printf("Worker %s: dropping duplicate delivery of running job %s", w.id, jobId)
+			if err := w.queueMgr.DropDuplicate(ctx, queueName, jobId); err != nil {
+				log.Printf("Worker %s: failed to drop duplicate of %s: %v", w.id, jobId, err)
+			}
+			return nil
+		}
+		log.Printf("Worker %s: skipping job %s in status %s", w.id, jobId, job.Status)
+		if err := w.queueMgr.AckProcessing(ctx, queueName, jobId); err != nil {
+			log.Printf("Worker %s: failed to ack processing for %s: %v", w.id, jobId, err)
+		}
+		return nil
+	}
+	w.publishStatus(jobId, "RUNNING", false)
 
-	// Execute the command with context
-	cmd := exec.CommandContext(ctx, "sh", "-c", job.Command)
-	output, err := cmd.CombinedOutput()
+	// Enforce the per-job deadline; expiry kills the whole process group like a cancel
+	execCtx := context.Context(jobCtx)
+	if job.TimeoutSeconds > 0 {
+		var cancelTimeout context.CancelFunc
+		execCtx, cancelTimeout = context.WithTimeoutCause(jobCtx, time.Duration(job.TimeoutSeconds)*time.Second, errJobTimedOut)
+		defer cancelTimeout()
+	}
+
+	// Execute the command in its own process group
+	cmd := exec.CommandContext(execCtx, "sh", "-c", job.Command)
+	killGroupOnCancel(cmd)
+	// Collect stdout and stderr together while streaming them for live tailing
+	out := newOutputStreamer(w.queueMgr, jobId)
+	cmd.Stdout = out.writer(queue.EVENT_STDOUT)
+	cmd.Stderr = out.writer(queue.EVENT_STDERR)
+	started := time.Now()
+	err = cmd.Run()
+	output := out.Bytes()
+	result := executionResult(cmd, time.Since(started))
+	result.Stdout, result.Stderr = out.Streams()
 
 	// Update job status based on execution result
 	status := "SUCCEEDED"
@@ -143,7 +368,15 @@ func (w *Worker) processNextJob(ctx context.Context) error {
 
 	if err != nil {
 		status = "FAILED"
-		if ctx.Err() != nil {
+		if context.Cause(execCtx) == errCancelRequested {
+			status = "CANCELLED"
+			outputStr = "Job cancelled by request: " + outputStr
+			log.Printf("Worker %s: job %s cancelled by request", w.id, jobId)
+		} else if context.Cause(execCtx) == errJobTimedOut {
+			status = "TIMED_OUT"
+			outputStr = fmt.Sprintf("Job timed out after %ds: %s", job.TimeoutSeconds, outputStr)
+			log.Printf("Worker %s: job %s timed out after %ds", w.id, jobId, job.TimeoutSeconds)
+		} else if ctx.Err() != nil {
 			// Job was cancelled due to context
 			outputStr = "Job cancelled: " + outputStr
 			log.Printf("Worker %s: job %s cancelled", w.id, jobId)
@@ -157,7 +390,7 @@ func (w *Worker) processNextJob(ctx context.Context) error {
 
 	// Persist status
 	for retries := 0; retries < MAX_RETRIES; retries++ {
-		if err := w.dbMgr.UpdateJobStatus(jobId, status, outputStr); err == nil {
+		if err := w.dbMgr.FinishAttempt(jobId, attempt, status, outputStr, result); err == nil {
 			break
 		}
 		log.Printf("Worker %s failed to update final status for job %s (attempt %d/%d): %v", w.id, jobId, retries+1, MAX_RETRIES, err)
@@ -167,10 +400,12 @@ func (w *Worker) processNextJob(ctx context.Context) error {
 	}
 
 	// Queue ack / retry / DLQ
-	if status == "SUCCEEDED" {
-		if err := w.queueMgr.AckProcessing(ctx, jobId); err != nil {
+	if status == "SUCCEEDED" || status == "CANCELLED" {
+		if err := w.queueMgr.AckProcessing(ctx, queueName, jobId); err != nil {
 			log.Printf("Worker %s: failed to ack processing for %s: %v", w.id, jobId, err)
 		}
+		w.publishStatus(jobId, status, true)
+		w.settleDependents(job, status)
 		return nil
 	}
 
@@ -179,30 +414,222 @@ func (w *Worker) processNextJob(ctx context.Context) error {
 	if incErr != nil {
 		log.Printf("Worker %s: failed to increment retry for %s: %v", w.id, jobId, incErr)
 		// best effort: move back to pending
-		_ = w.queueMgr.RequeueFromProcessing(ctx, jobId)
+		_ = w.queueMgr.RequeueFromProcessing(ctx, queueName, jobId)
 		return incErr
 	}
-	if retries <= max {
-		// Reset status to PENDING and requeue
+	if retries <= max && retryable(job, status, result.ExitCode) {
+		// Reset status to PENDING and park the job until its backoff expires
+		delay := retryDelay(job, retries)
+		retryAt := time.Now().Add(delay)
 		_ = w.dbMgr.ResetToPending(jobId, outputStr)
-		if err := w.queueMgr.RequeueFromProcessing(ctx, jobId); err != nil {
-			log.Printf("Worker %s: failed to requeue %s: %v", w.id, jobId, err)
+		if err := w.queueMgr.DelayFromProcessing(ctx, queueName, jobId, retryAt); err != nil {
+			log.Printf("Worker %s: failed to delay %s: %v", w.id, jobId, err)
 			return err
 		}
-		log.Printf("Worker %s: requeued job %s (retry %d/%d)", w.id, jobId, retries, max)
+		w.publishStatus(jobId, "PENDING", false)
+		log.Printf("Worker %s: job %s will retry in %v (retry %d/%d)", w.id, jobId, delay.Round(time.Millisecond), retries, max)
 	} else {
-		if err := w.queueMgr.MoveToDLQ(ctx, jobId); err != nil {
+		if err := w.queueMgr.MoveToDLQ(ctx, queueName, jobId); err != nil {
 			log.Printf("Worker %s: failed to move %s to DLQ: %v", w.id, jobId, err)
 			return err
 		}
-		log.Printf("Worker %s: moved job %s to DLQ after %d retries", w.id, jobId, retries-1)
+		w.publishStatus(jobId, status, true)
+		w.settleDependents(job, status)
+		if retries <= max {
+			log.Printf("Worker %s: moved job %s to DLQ, exit code %d is not retryable", w.id, jobId, result.ExitCode)
+		} else {
+			log.Printf("Worker %s: moved job %s to DLQ after %d retries", w.id, jobId, retries-1)
+		}
 	}
 
 	return nil
 }
 
+// publishStatus tells watchers of jobId about a status change. Watchers fall back to
+// polling the database, so failures are only logged.
+func (w *Worker) publishStatus(jobId, status string, final bool) {
+	if err := w.queueMgr.PublishJobStatus(context.Background(), jobId, status, final); err != nil {
+		log.Printf("Worker %s: failed to publish status of %s: %v", w.id, jobId, err)
+	}
+}
+
+// settleDependents releases workflow jobs unblocked by a successful job, which the servers'
+// outbox relay then enqueues, or skips everything downstream of one that will not run again.
+// The leader does the same on its next tick if this fails.
+func (w *Worker) settleDependents(job *db.Job, status string) {
+	if !job.WorkflowID.Valid {
+		return
+	}
+	if status != "SUCCEEDED" {
+		if n, err := w.dbMgr.SkipDependents(job.ID); err != nil {
+			log.Printf("Worker %s: failed to skip dependents of %s: %v", w.id, job.ID, err)
+		} else if n > 0 {
+			log.Printf("Worker %s: skipped %d jobs depending on %s", w.id, n, job.ID)
+		}
+		return
+	}
+	released, err := w.dbMgr.ReleaseBlockedTasks(job.ID)
+	if err != nil {
+		log.Printf("Worker %s: failed to release dependents of %s: %v", w.id, job.ID, err)
+		return
+	}
+	for _, id := range released {
+		log.Printf("Worker %s: released job %s after %s succeeded", w.id, id, job.ID)
+	}
+}
+
+// recoverRedelivery settles the attempt a dead worker left behind on a redelivered job,
+// like the leader does for orphaned processing entries. It returns true if the job was
+// dead-lettered and must not run.
+func (w *Worker) recoverRedelivery(ctx context.Context, job *db.Job, queueName string, deliveries int64) bool {
+	if job.Status != "RUNNING" {
+		// Popped but never started, e.g. handed back by a paused worker: run it without
+		// spending a retry, however often it was delivered. ClaimJob skips finished jobs.
+		return false
+	}
+	output := job.Output.String + "\n[auto] worker lease expired"
+	if deliveries > MAX_DELIVERIES {
+		output = fmt.Sprintf("%s\n[auto] delivered %d times without finishing", job.Output.String, deliveries)
+	} else {
+		retries, max, err := w.dbMgr.IncrementRetry(job.ID)
+		if err != nil {
+			log.Printf("Worker %s: failed to count retry of %s: %v", w.id, job.ID, err)
+			return false
+		}
+		if retries <= max {
+			if err := w.dbMgr.ResetToPending(job.ID, output); err != nil {
+				log.Printf("Worker %s: failed to reset %s: %v", w.id, job.ID, err)
+				return false
+			}
+			log.Printf("Worker %s: taking over job %s from a dead worker (retry %d/%d)", w.id, job.ID, retries, max)
+			return false
+		}
+	}
+
+	log.Printf("Worker %s: moving job %s to DLQ after %d deliveries", w.id, job.ID, deliveries)
+	if err := w.dbMgr.UpdateJobStatus(job.ID, "FAILED", output); err != nil {
+		log.Printf("Worker %s: failed to mark %s failed: %v", w.id, job.ID, err)
+	}
+	w.publishStatus(job.ID, "FAILED", true)
+	w.settleDependents(job, "FAILED")
+	if err := w.queueMgr.MoveToDLQ(ctx, queueName, job.ID); err != nil {
+		log.Printf("Worker %s: failed to move %s to DLQ: %v", w.id, job.ID, err)
+	}
+	return true
+}
+
+// heartbeat renews the lease on jobId and touches its task row until stop is closed.
+func (w *Worker) heartbeat(jobId string, stop <-chan struct{}) {
+	ticker := time.NewTicker(HEARTBEAT_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := w.queueMgr.RenewLease(context.Background(), jobId, w.id); err != nil {
+				log.Printf("Worker %s: failed to renew lease on %s: %v", w.id, jobId, err)
+			}
+			if err := w.dbMgr.TouchJob(jobId); err != nil {
+				log.Printf("Worker %s: failed to touch job %s: %v", w.id, jobId, err)
+			}
+		}
+	}
+}
+
+// advertise keeps the worker's entry in the queue's worker registry current until stop is
+// closed, then deregisters it. Every CONTROL_INTERVAL it takes the run state asked for
+// through the registry and re-registers if that or the running jobs changed, so the
+// registry shows the progress of a drain; otherwise it re-registers every
+// HEARTBEAT_INTERVAL to renew the lease.
+func (w *Worker) advertise(stop <-chan struct{}) {
+	ticker := time.NewTicker(CONTROL_INTERVAL)
+	defer ticker.Stop()
+	var last queue.WorkerInfo
+	var lastAt time.Time
+	for {
+		w.pollControl()
+		info := w.info()
+		if time.Since(lastAt) >= HEARTBEAT_INTERVAL || info.State != last.State || !slices.Equal(info.Running, last.Running) {
+			if err := w.queueMgr.RegisterWorker(context.Background(), info); err != nil {
+				log.Printf("Worker %s: failed to register: %v", w.id, err)
+			} else {
+				last, lastAt = info, time.Now()
+			}
+		}
+		w.checkDrained()
+		select {
+		case <-stop:
+			if err := w.queueMgr.DeregisterWorker(context.Background(), w.id); err != nil {
+				log.Printf("Worker %s: failed to deregister: %v", w.id, err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// info describes the worker for the registry.
+func (w *Worker) info() queue.WorkerInfo {
+	queues := make([]string, len(w.queues))
+	for i, q := range w.queues {
+		queues[i] = fmt.Sprintf("%s:%d", q.Name, q.Weight)
+	}
+	w.mu.Lock()
+	running := make([]string, 0, len(w.running))
+	for jobId := range w.running {
+		running = append(running, jobId)
+	}
+	state := w.state
+	w.mu.Unlock()
+	sort.Strings(running)
+	return queue.WorkerInfo{
+		ID:        w.id,
+		Host:      w.host,
+		PID:       os.Getpid(),
+		Version:   Version,
+		Queues:    queues,
+		Slots:     w.slots,
+		Running:   running,
+		State:     state,
+		StartedAt: w.startedAt,
+	}
+}
+
+// watchCancellations kills local jobs named on the cancellation channel.
+func (w *Worker) watchCancellations(cancels <-chan string) {
+	for jobId := range cancels {
+		w.mu.Lock()
+		cancel, ok := w.running[jobId]
+		w.mu.Unlock()
+		if ok {
+			log.Printf("Worker %s: cancelling job %s", w.id, jobId)
+			cancel(errCancelRequested)
+		}
+	}
+}
+
+func (w *Worker) track(jobId string, cancel context.CancelCauseFunc) {
+	w.mu.Lock()
+	w.running[jobId] = cancel
+	w.mu.Unlock()
+}
+
+func (w *Worker) untrack(jobId string) {
+	w.mu.Lock()
+	delete(w.running, jobId)
+	w.mu.Unlock()
+}
+
+func (w *Worker) inFlight() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.running)
+}
+
 func (w *Worker) Close() error {
 	log.Printf("Worker %s cleaning up...", w.id)
+	w.killJobs()
 	var dbErr, queueErr error
 
 	if w.dbMgr != nil {
@@ -0,0 +1,236 @@
+package worker
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"distributed-task-scheduler/internal/db"
+	"distributed-task-scheduler/internal/queue"
+)
+
+func TestRetryDelayBacksOffWithJitter(t *testing.T) {
+	job := &db.Job{RetryBackoffSeconds: 2, RetryMultiplier: 3, RetryMaxBackoffSeconds: 60}
+	for _, tc := range []struct {
+		attempt int32
+		base    time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 6 * time.Second},
+		{3, 18 * time.Second},
+		{5, time.Minute}, // capped
+	} {
+		lo := time.Duration(float64(tc.base) * (1 - RETRY_JITTER))
+		hi := time.Duration(float64(tc.base) * (1 + RETRY_JITTER))
+		for i := 0; i < 100; i++ {
+			if d := retryDelay(job, tc.attempt); d < lo || d > hi {
+				t.Fatalf("retryDelay(attempt %d) = %v, want within [%v, %v]", tc.attempt, d, lo, hi)
+			}
+		}
+	}
+}
+
+func TestRetryableExitCodes(t *testing.T) {
+	unlisted := &db.Job{}
+	if !retryable(unlisted, "FAILED", 1) {
+		t.Fatal("failure not retryable without an exit code list")
+	}
+	listed := &db.Job{RetryExitCodes: []int32{75}}
+	if !retryable(listed, "FAILED", 75) || retryable(listed, "FAILED", 1) {
+		t.Fatal("exit code list not honoured")
+	}
+	if !retryable(listed, "TIMED_OUT", -1) {
+		t.Fatal("timeout not retryable")
+	}
+}
+
+func TestParseQueueWeights(t *testing.T) {
+	queues, err := ParseQueueWeights(" default , batch:3,,reports:1000")
+	if err != nil {
+		t.Fatalf("ParseQueueWeights: %v", err)
+	}
+	want := []QueueWeight{{Name: "default", Weight: 1}, {Name: "batch", Weight: 3}, {Name: "reports", Weight: 1000}}
+	if !slices.Equal(queues, want) {
+		t.Fatalf("ParseQueueWeights = %v, want %v", queues, want)
+	}
+
+	for _, tc := range []struct {
+		spec string
+		want string
+	}{
+		{"", "no queues"},
+		{" , ", "no queues"},
+		{"batch:", `invalid weight ""`},
+		{"batch:x", `invalid weight "x"`},
+		{"batch:1.5", `invalid weight "1.5"`},
+		{"batch:0", `invalid weight "0"`},
+		{"batch:-2", `invalid weight "-2"`},
+		{"batch:1001", `invalid weight "1001"`},
+		{"batch:99999999999999999999", "invalid weight"},
+		{"batch:2:3", `invalid weight "2:3"`},
+		{":2", "queue name"},
+		{"batch,default,batch:2", "listed more than once"},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			_, err := ParseQueueWeights(tc.spec)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("ParseQueueWeights(%q) = %v, want an error containing %q", tc.spec, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestPollOrderCoversEveryQueue(t *testing.T) {
+	queues := []QueueWeight{{Name: "a", Weight: 3}, {Name: "b", Weight: 1}, {Name: "c", Weight: 1}}
+	first := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		order := pollOrder(queues)
+		sorted := slices.Clone(order)
+		slices.Sort(sorted)
+		if !slices.Equal(sorted, []string{"a", "b", "c"}) {
+			t.Fatalf("pollOrder = %v", order)
+		}
+		first[order[0]]++
+	}
+	// a carries 60% of the weight
+	if first["a"] < 500 || first["a"] > 700 || first["b"] == 0 || first["c"] == 0 {
+		t.Fatalf("first queue counts %v", first)
+	}
+}
+
+func TestControlStates(t *testing.T) {
+	q := queue.NewMemoryQueue()
+	w, err := NewWorkerWith("w1", db.NewMemoryStore(), q, 1, []QueueWeight{{Name: queue.DEFAULT_QUEUE, Weight: 1}})
+	if err != nil {
+		t.Fatalf("NewWorkerWith: %v", err)
+	}
+	ctx := context.Background()
+	if ok, _ := w.accepting(); !ok {
+		t.Fatal("new worker does not take jobs")
+	}
+
+	q.SetWorkerControl(ctx, "w1", queue.WORKER_PAUSED)
+	w.pollControl()
+	ok, changed := w.accepting()
+	if ok {
+		t.Fatal("paused worker takes jobs")
+	}
+	q.SetWorkerControl(ctx, "w1", queue.WORKER_RUNNING)
+	w.pollControl()
+	select {
+	case <-changed:
+	default:
+		t.Fatal("resume did not wake waiting slots")
+	}
+
+	// A draining worker is done once its last job finished
+	w.running["job"] = func(error) {}
+	q.SetWorkerControl(ctx, "w1", queue.WORKER_DRAINING)
+	w.pollControl()
+	w.checkDrained()
+	select {
+	case <-w.drained:
+		t.Fatal("drained with a job in flight")
+	default:
+	}
+	delete(w.running, "job")
+	w.checkDrained()
+	w.checkDrained() // closes drained once
+	select {
+	case <-w.drained:
+	default:
+		t.Fatal("idle draining worker not drained")
+	}
+}
+
+// countingQueue counts every delivery of a job and never resets the count, like a stream
+// whose entries are handed back without being re-added. handBack runs after each requeue.
+type countingQueue struct {
+	*queue.MemoryQueue
+	mu         sync.Mutex
+	deliveries map[string]int64
+	popped     func(jobId string, n int64)
+	handBack   func()
+}
+
+func (q *countingQueue) PopJob(ctx context.Context, queues []string) (string, string, error) {
+	id, name, err := q.MemoryQueue.PopJob(ctx, queues)
+	if err == nil {
+		q.mu.Lock()
+		q.deliveries[id]++
+		n := q.deliveries[id]
+		q.mu.Unlock()
+		q.popped(id, n)
+	}
+	return id, name, err
+}
+
+func (q *countingQueue) Deliveries(ctx context.Context, queue, jobId string) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.deliveries[jobId], nil
+}
+
+func (q *countingQueue) RequeueFromProcessing(ctx context.Context, queue, jobId string) error {
+	err := q.MemoryQueue.RequeueFromProcessing(ctx, queue, jobId)
+	q.handBack()
+	return err
+}
+
+func TestPauseAndResumeDoNotDeadLetterQueuedJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	store := db.NewMemoryStore()
+	q := &countingQueue{MemoryQueue: queue.NewMemoryQueue(), deliveries: map[string]int64{}}
+	w, err := NewWorkerWith("w1", store, q, 1, []QueueWeight{{Name: queue.DEFAULT_QUEUE, Weight: 1}})
+	if err != nil {
+		t.Fatalf("NewWorkerWith: %v", err)
+	}
+	control := func(state string) {
+		q.SetWorkerControl(ctx, "w1", state)
+		w.pollControl()
+	}
+	// Every delivery but the last arrives just as the worker is paused, so the worker hands
+	// the job back; the hand-back is followed by a resume
+	pauses := MAX_DELIVERIES + 2
+	q.popped = func(jobId string, n int64) {
+		if n <= int64(pauses) {
+			control(queue.WORKER_PAUSED)
+		}
+	}
+	q.handBack = func() { control(queue.WORKER_RUNNING) }
+
+	if _, err := store.CreateJob(&db.Job{ID: "job", Command: "echo ran", Queue: queue.DEFAULT_QUEUE}, 0); err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	q.PushJob(ctx, "", "job", 0)
+	done := make(chan error, 1)
+	go func() { done <- w.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		job, err := store.GetJob("job")
+		if err != nil {
+			t.Fatalf("GetJob: %v", err)
+		}
+		if job.Status == "SUCCEEDED" {
+			break
+		}
+		if job.Status != "PENDING" && job.Status != "RUNNING" || time.Now().After(deadline) {
+			t.Fatalf("job is %s (%s), want it to run", job.Status, job.Output.String)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if n, _ := q.Deliveries(ctx, "", "job"); n <= MAX_DELIVERIES {
+		t.Fatalf("job delivered %d times, want more than MAX_DELIVERIES", n)
+	}
+	if dead, _ := q.IsDeadLetter(ctx, "", "job"); dead {
+		t.Fatal("job was dead-lettered")
+	}
+}
@@ -254,12 +254,20 @@ func (x *TaskStatus) GetResult() string {
 }
 
 type Job struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Command       string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Command        string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	CreatedAt      int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ScheduleTime   int64                  `protobuf:"varint,4,opt,name=schedule_time,json=scheduleTime,proto3" json:"schedule_time,omitempty"`       // Unix seconds; run once at this time (0 = immediately)
+	CronExpr       string                 `protobuf:"bytes,5,opt,name=cron_expr,json=cronExpr,proto3" json:"cron_expr,omitempty"`                    // Cron expression for recurring jobs (optional seconds field)
+	Timezone       string                 `protobuf:"bytes,6,opt,name=timezone,proto3" json:"timezone,omitempty"`                                    // IANA zone for cron_expr, e.g. "Europe/Berlin" (default: server local)
+	TimeoutSeconds int64                  `protobuf:"varint,7,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // Kill the command after this many seconds (0 = no limit)
+	Priority       int32                  `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`                                   // Higher runs first; waiting time gradually raises it (default 0)
+	Queue          string                 `protobuf:"bytes,9,opt,name=queue,proto3" json:"queue,omitempty"`                                          // Named queue the job is routed to (default "default")
+	RetryPolicy    *RetryPolicy           `protobuf:"bytes,10,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`          // How failed runs are retried (default: 4 attempts, 1s..300s backoff)
+	IdempotencyKey string                 `protobuf:"bytes,11,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // Resubmitting with the same key within the server's window returns the original job
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Job) Reset() {
@@ -313,18 +321,152 @@ func (x *Job) GetCreatedAt() int64 {
 	return 0
 }
 
+func (x *Job) GetScheduleTime() int64 {
+	if x != nil {
+		return x.ScheduleTime
+	}
+	return 0
+}
+
+func (x *Job) GetCronExpr() string {
+	if x != nil {
+		return x.CronExpr
+	}
+	return ""
+}
+
+func (x *Job) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Job) GetTimeoutSeconds() int64 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *Job) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Job) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *Job) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *Job) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type RetryPolicy struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	MaxAttempts           int32                  `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`                                 // Runs including the first one (0 = 4)
+	InitialBackoffSeconds int64                  `protobuf:"varint,2,opt,name=initial_backoff_seconds,json=initialBackoffSeconds,proto3" json:"initial_backoff_seconds,omitempty"` // Delay before the first retry (0 = 1)
+	BackoffMultiplier     float64                `protobuf:"fixed64,3,opt,name=backoff_multiplier,json=backoffMultiplier,proto3" json:"backoff_multiplier,omitempty"`              // Growth of the delay per retry, at least 1 (0 = 2)
+	MaxBackoffSeconds     int64                  `protobuf:"varint,4,opt,name=max_backoff_seconds,json=maxBackoffSeconds,proto3" json:"max_backoff_seconds,omitempty"`             // Upper bound for a single delay (0 = 300)
+	RetryableExitCodes    []int32                `protobuf:"varint,5,rep,packed,name=retryable_exit_codes,json=retryableExitCodes,proto3" json:"retryable_exit_codes,omitempty"`   // Retry only these exit codes; timeouts always retry (empty = any failure)
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	mi := &file_proto_scheduler_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RetryPolicy) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetInitialBackoffSeconds() int64 {
+	if x != nil {
+		return x.InitialBackoffSeconds
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetBackoffMultiplier() float64 {
+	if x != nil {
+		return x.BackoffMultiplier
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetMaxBackoffSeconds() int64 {
+	if x != nil {
+		return x.MaxBackoffSeconds
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetRetryableExitCodes() []int32 {
+	if x != nil {
+		return x.RetryableExitCodes
+	}
+	return nil
+}
+
 type JobResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
 	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`        // Current status of the job
+	Duplicate     bool                   `protobuf:"varint,5,opt,name=duplicate,proto3" json:"duplicate,omitempty"` // The idempotency key matched an earlier submission; job_id is that job
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *JobResponse) Reset() {
 	*x = JobResponse{}
-	mi := &file_proto_scheduler_proto_msgTypes[5]
+	mi := &file_proto_scheduler_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -336,7 +478,7 @@ func (x *JobResponse) String() string {
 func (*JobResponse) ProtoMessage() {}
 
 func (x *JobResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_scheduler_proto_msgTypes[5]
+	mi := &file_proto_scheduler_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -349,7 +491,7 @@ func (x *JobResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobResponse.ProtoReflect.Descriptor instead.
 func (*JobResponse) Descriptor() ([]byte, []int) {
-	return file_proto_scheduler_proto_rawDescGZIP(), []int{5}
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *JobResponse) GetJobId() string {
@@ -373,6 +515,20 @@ func (x *JobResponse) GetMessage() string {
 	return ""
 }
 
+func (x *JobResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
 type JobId struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -382,7 +538,7 @@ type JobId struct {
 
 func (x *JobId) Reset() {
 	*x = JobId{}
-	mi := &file_proto_scheduler_proto_msgTypes[6]
+	mi := &file_proto_scheduler_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -394,7 +550,7 @@ func (x *JobId) String() string {
 func (*JobId) ProtoMessage() {}
 
 func (x *JobId) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_scheduler_proto_msgTypes[6]
+	mi := &file_proto_scheduler_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -407,7 +563,7 @@ func (x *JobId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobId.ProtoReflect.Descriptor instead.
 func (*JobId) Descriptor() ([]byte, []int) {
-	return file_proto_scheduler_proto_rawDescGZIP(), []int{6}
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *JobId) GetId() string {
@@ -420,17 +576,23 @@ func (x *JobId) GetId() string {
 type JobStatus struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // PENDING, RUNNING, SUCCEEDED, FAILED
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // PENDING, RUNNING, SUCCEEDED, FAILED, CANCELLED, TIMED_OUT, BLOCKED, SKIPPED
 	Output        string                 `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"` // Command output (stdout + stderr)
 	CreatedAt     int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	NextRunAt     int64                  `protobuf:"varint,6,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"` // Unix seconds of the next scheduled run (0 = none)
+	CronExpr      string                 `protobuf:"bytes,7,opt,name=cron_expr,json=cronExpr,proto3" json:"cron_expr,omitempty"`
+	Priority      int32                  `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	Queue         string                 `protobuf:"bytes,9,opt,name=queue,proto3" json:"queue,omitempty"`
+	WorkflowId    string                 `protobuf:"bytes,10,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"` // Set for jobs submitted as part of a workflow
+	Result        *ExecutionResult       `protobuf:"bytes,11,opt,name=result,proto3" json:"result,omitempty"`                           // Outcome of the latest finished attempt (unset before one finishes)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *JobStatus) Reset() {
 	*x = JobStatus{}
-	mi := &file_proto_scheduler_proto_msgTypes[7]
+	mi := &file_proto_scheduler_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -442,7 +604,7 @@ func (x *JobStatus) String() string {
 func (*JobStatus) ProtoMessage() {}
 
 func (x *JobStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_scheduler_proto_msgTypes[7]
+	mi := &file_proto_scheduler_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -455,7 +617,7 @@ func (x *JobStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
 func (*JobStatus) Descriptor() ([]byte, []int) {
-	return file_proto_scheduler_proto_rawDescGZIP(), []int{7}
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *JobStatus) GetId() string {
@@ -493,54 +655,2126 @@ func (x *JobStatus) GetUpdatedAt() int64 {
 	return 0
 }
 
-var File_proto_scheduler_proto protoreflect.FileDescriptor
+func (x *JobStatus) GetNextRunAt() int64 {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return 0
+}
 
-const file_proto_scheduler_proto_rawDesc = "" +
-	"\n" +
-	"\x15proto/scheduler.proto\x12\tscheduler\"i\n" +
-	"\x04Task\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
-	"\acommand\x18\x03 \x01(\tR\acommand\x12#\n" +
-	"\rschedule_time\x18\x04 \x01(\x03R\fscheduleTime\"[\n" +
-	"\fTaskResponse\x12\x17\n" +
-	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x18\n" +
-	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"\x18\n" +
-	"\x06TaskId\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"L\n" +
-	"\n" +
-	"TaskStatus\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
-	"\x06result\x18\x03 \x01(\tR\x06result\"N\n" +
-	"\x03Job\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
-	"\acommand\x18\x02 \x01(\tR\acommand\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x03 \x01(\x03R\tcreatedAt\"X\n" +
-	"\vJobResponse\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x18\n" +
-	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"\x17\n" +
-	"\x05JobId\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x89\x01\n" +
-	"\tJobStatus\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
-	"\x06output\x18\x03 \x01(\tR\x06output\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+func (x *JobStatus) GetCronExpr() string {
+	if x != nil {
+		return x.CronExpr
+	}
+	return ""
+}
+
+func (x *JobStatus) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *JobStatus) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *JobStatus) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *JobStatus) GetResult() *ExecutionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// ExecutionResult describes how one run of a job's command ended.
+type ExecutionResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stdout        string                 `protobuf:"bytes,1,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr        string                 `protobuf:"bytes,2,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	ExitCode      int32                  `protobuf:"varint,3,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"` // -1 when the process did not exit normally (see signal) or did not start
+	Signal        string                 `protobuf:"bytes,4,opt,name=signal,proto3" json:"signal,omitempty"`                      // Name of the signal that killed the process, e.g. "killed"
+	WallTimeMs    int64                  `protobuf:"varint,5,opt,name=wall_time_ms,json=wallTimeMs,proto3" json:"wall_time_ms,omitempty"`
+	UserCpuMs     int64                  `protobuf:"varint,6,opt,name=user_cpu_ms,json=userCpuMs,proto3" json:"user_cpu_ms,omitempty"`
+	SystemCpuMs   int64                  `protobuf:"varint,7,opt,name=system_cpu_ms,json=systemCpuMs,proto3" json:"system_cpu_ms,omitempty"`
+	MaxRssKb      int64                  `protobuf:"varint,8,opt,name=max_rss_kb,json=maxRssKb,proto3" json:"max_rss_kb,omitempty"` // Peak resident set size of the process tree
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecutionResult) Reset() {
+	*x = ExecutionResult{}
+	mi := &file_proto_scheduler_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionResult) ProtoMessage() {}
+
+func (x *ExecutionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionResult.ProtoReflect.Descriptor instead.
+func (*ExecutionResult) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExecutionResult) GetStdout() string {
+	if x != nil {
+		return x.Stdout
+	}
+	return ""
+}
+
+func (x *ExecutionResult) GetStderr() string {
+	if x != nil {
+		return x.Stderr
+	}
+	return ""
+}
+
+func (x *ExecutionResult) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *ExecutionResult) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *ExecutionResult) GetWallTimeMs() int64 {
+	if x != nil {
+		return x.WallTimeMs
+	}
+	return 0
+}
+
+func (x *ExecutionResult) GetUserCpuMs() int64 {
+	if x != nil {
+		return x.UserCpuMs
+	}
+	return 0
+}
+
+func (x *ExecutionResult) GetSystemCpuMs() int64 {
+	if x != nil {
+		return x.SystemCpuMs
+	}
+	return 0
+}
+
+func (x *ExecutionResult) GetMaxRssKb() int64 {
+	if x != nil {
+		return x.MaxRssKb
+	}
+	return 0
+}
+
+type ListJobsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Statuses        []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`                                      // Match any of these statuses (empty = all)
+	CreatedAfter    int64                  `protobuf:"varint,2,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`         // Unix seconds, inclusive (0 = unbounded)
+	CreatedBefore   int64                  `protobuf:"varint,3,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`      // Unix seconds, exclusive (0 = unbounded)
+	UpdatedAfter    int64                  `protobuf:"varint,4,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`         // Unix seconds, inclusive (0 = unbounded)
+	UpdatedBefore   int64                  `protobuf:"varint,5,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`      // Unix seconds, exclusive (0 = unbounded)
+	CommandContains string                 `protobuf:"bytes,6,opt,name=command_contains,json=commandContains,proto3" json:"command_contains,omitempty"` // Case-insensitive substring of the command
+	Kind            string                 `protobuf:"bytes,7,opt,name=kind,proto3" json:"kind,omitempty"`                                              // "cron", "oneshot" or empty for both
+	OrderBy         string                 `protobuf:"bytes,8,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`                         // "created_at" (default) or "updated_at"
+	Ascending       bool                   `protobuf:"varint,9,opt,name=ascending,proto3" json:"ascending,omitempty"`                                   // Oldest first instead of newest first
+	PageSize        int32                  `protobuf:"varint,10,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`                    // Default 50, max 500
+	PageToken       string                 `protobuf:"bytes,11,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                  // next_page_token from a previous response
+	Queue           string                 `protobuf:"bytes,12,opt,name=queue,proto3" json:"queue,omitempty"`                                           // Only jobs routed to this queue (empty = all)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListJobsRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetCreatedAfter() int64 {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetCreatedBefore() int64 {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetUpdatedAfter() int64 {
+	if x != nil {
+		return x.UpdatedAfter
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetUpdatedBefore() int64 {
+	if x != nil {
+		return x.UpdatedBefore
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetCommandContains() string {
+	if x != nil {
+		return x.CommandContains
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetAscending() bool {
+	if x != nil {
+		return x.Ascending
+	}
+	return false
+}
+
+func (x *ListJobsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+type ListJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*JobStatus           `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty when there are no more results
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListJobsResponse) GetJobs() []*JobStatus {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+func (x *ListJobsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type DeadLetter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Queue         string                 `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	Command       string                 `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                           // Final status, FAILED or TIMED_OUT
+	LastOutput    string                 `protobuf:"bytes,5,opt,name=last_output,json=lastOutput,proto3" json:"last_output,omitempty"` // Output of the last attempt
+	Attempts      int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`                      // Runs made before the job was dead-lettered
+	FailedAt      int64                  `protobuf:"varint,7,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`      // Unix seconds
+	Priority      int32                  `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_proto_scheduler_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeadLetter) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetLastOutput() string {
+	if x != nil {
+		return x.LastOutput
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetFailedAt() int64 {
+	if x != nil {
+		return x.FailedAt
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+type ListDeadLettersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`  // Only this queue (empty = all queues)
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // Default 50, max 500
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListDeadLettersRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ListDeadLettersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetters   []*DeadLetter          `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"` // Grouped by queue, oldest first
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                               // Dead letters in the selected queues, ignoring limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersResponse) Reset() {
+	*x = ListDeadLettersResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersResponse) ProtoMessage() {}
+
+func (x *ListDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListDeadLettersResponse) GetDeadLetters() []*DeadLetter {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+func (x *ListDeadLettersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type PurgeDeadLettersRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Queue           string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`                                            // Only this queue (empty = all queues)
+	Ids             []string               `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`                                                // Only these jobs
+	FailedBefore    int64                  `protobuf:"varint,3,opt,name=failed_before,json=failedBefore,proto3" json:"failed_before,omitempty"`         // Unix seconds, exclusive (0 = unbounded)
+	CommandContains string                 `protobuf:"bytes,4,opt,name=command_contains,json=commandContains,proto3" json:"command_contains,omitempty"` // Case-insensitive substring of the command
+	All             bool                   `protobuf:"varint,5,opt,name=all,proto3" json:"all,omitempty"`                                               // Required to purge without any other filter
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PurgeDeadLettersRequest) Reset() {
+	*x = PurgeDeadLettersRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDeadLettersRequest) ProtoMessage() {}
+
+func (x *PurgeDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*PurgeDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PurgeDeadLettersRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *PurgeDeadLettersRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *PurgeDeadLettersRequest) GetFailedBefore() int64 {
+	if x != nil {
+		return x.FailedBefore
+	}
+	return 0
+}
+
+func (x *PurgeDeadLettersRequest) GetCommandContains() string {
+	if x != nil {
+		return x.CommandContains
+	}
+	return ""
+}
+
+func (x *PurgeDeadLettersRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type PurgeDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Purged        int32                  `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDeadLettersResponse) Reset() {
+	*x = PurgeDeadLettersResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDeadLettersResponse) ProtoMessage() {}
+
+func (x *PurgeDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*PurgeDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PurgeDeadLettersResponse) GetPurged() int32 {
+	if x != nil {
+		return x.Purged
+	}
+	return 0
+}
+
+type WorkflowNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                            // Unique within the workflow
+	Job           *Job                   `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`                              // schedule_time and cron_expr are not allowed
+	DependsOn     []string               `protobuf:"bytes,3,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"` // Names of nodes that must succeed first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowNode) Reset() {
+	*x = WorkflowNode{}
+	mi := &file_proto_scheduler_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowNode) ProtoMessage() {}
+
+func (x *WorkflowNode) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowNode.ProtoReflect.Descriptor instead.
+func (*WorkflowNode) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WorkflowNode) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkflowNode) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+func (x *WorkflowNode) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+type Workflow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Generated when empty
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Nodes         []*WorkflowNode        `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Workflow) Reset() {
+	*x = Workflow{}
+	mi := &file_proto_scheduler_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Workflow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow) ProtoMessage() {}
+
+func (x *Workflow) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow.ProtoReflect.Descriptor instead.
+func (*Workflow) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Workflow) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Workflow) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Workflow) GetNodes() []*WorkflowNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type WorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	JobIds        map[string]string      `protobuf:"bytes,4,rep,name=job_ids,json=jobIds,proto3" json:"job_ids,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Node name -> job ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowResponse) Reset() {
+	*x = WorkflowResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowResponse) ProtoMessage() {}
+
+func (x *WorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowResponse.ProtoReflect.Descriptor instead.
+func (*WorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WorkflowResponse) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *WorkflowResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *WorkflowResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *WorkflowResponse) GetJobIds() map[string]string {
+	if x != nil {
+		return x.JobIds
+	}
+	return nil
+}
+
+type WorkflowId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowId) Reset() {
+	*x = WorkflowId{}
+	mi := &file_proto_scheduler_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowId) ProtoMessage() {}
+
+func (x *WorkflowId) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowId.ProtoReflect.Descriptor instead.
+func (*WorkflowId) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WorkflowId) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type WorkflowNodeStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	JobId         string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // BLOCKED until all dependencies succeeded, SKIPPED if one did not
+	DependsOn     []string               `protobuf:"bytes,4,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowNodeStatus) Reset() {
+	*x = WorkflowNodeStatus{}
+	mi := &file_proto_scheduler_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowNodeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowNodeStatus) ProtoMessage() {}
+
+func (x *WorkflowNodeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowNodeStatus.ProtoReflect.Descriptor instead.
+func (*WorkflowNodeStatus) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WorkflowNodeStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkflowNodeStatus) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *WorkflowNodeStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkflowNodeStatus) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *WorkflowNodeStatus) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type WorkflowStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // RUNNING, SUCCEEDED, FAILED or CANCELLED
+	CreatedAt     int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Nodes         []*WorkflowNodeStatus  `protobuf:"bytes,6,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowStatus) Reset() {
+	*x = WorkflowStatus{}
+	mi := &file_proto_scheduler_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowStatus) ProtoMessage() {}
+
+func (x *WorkflowStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowStatus.ProtoReflect.Descriptor instead.
+func (*WorkflowStatus) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WorkflowStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WorkflowStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkflowStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkflowStatus) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *WorkflowStatus) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *WorkflowStatus) GetNodes() []*WorkflowNodeStatus {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type TailJobLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Follow        bool                   `protobuf:"varint,2,opt,name=follow,proto3" json:"follow,omitempty"` // Keep streaming until the job settles
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TailJobLogsRequest) Reset() {
+	*x = TailJobLogsRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TailJobLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TailJobLogsRequest) ProtoMessage() {}
+
+func (x *TailJobLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TailJobLogsRequest.ProtoReflect.Descriptor instead.
+func (*TailJobLogsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *TailJobLogsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *TailJobLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+type LogChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stream        string                 `protobuf:"bytes,1,opt,name=stream,proto3" json:"stream,omitempty"` // "stdout", "stderr", or "output" for stored output once the live log expired
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	TimestampMs   int64                  `protobuf:"varint,3,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogChunk) Reset() {
+	*x = LogChunk{}
+	mi := &file_proto_scheduler_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogChunk) ProtoMessage() {}
+
+func (x *LogChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogChunk.ProtoReflect.Descriptor instead.
+func (*LogChunk) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LogChunk) GetStream() string {
+	if x != nil {
+		return x.Stream
+	}
+	return ""
+}
+
+func (x *LogChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *LogChunk) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+// JobAttempt is one run of a job on a worker.
+type JobAttempt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attempt       int32                  `protobuf:"varint,1,opt,name=attempt,proto3" json:"attempt,omitempty"` // 1 for the first run, counting across retries and cron runs
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Host          string                 `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                            // RUNNING while it runs, then the status it ended with
+	StartedAt     int64                  `protobuf:"varint,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`    // Unix seconds
+	FinishedAt    int64                  `protobuf:"varint,6,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"` // Unix seconds (0 = still running)
+	DurationMs    int64                  `protobuf:"varint,7,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"` // Time from start to finish, or so far while running
+	Output        string                 `protobuf:"bytes,8,opt,name=output,proto3" json:"output,omitempty"`                            // Output of this attempt, truncated
+	Result        *ExecutionResult       `protobuf:"bytes,9,opt,name=result,proto3" json:"result,omitempty"`                            // Unset when the worker never reported back
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobAttempt) Reset() {
+	*x = JobAttempt{}
+	mi := &file_proto_scheduler_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobAttempt) ProtoMessage() {}
+
+func (x *JobAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobAttempt.ProtoReflect.Descriptor instead.
+func (*JobAttempt) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *JobAttempt) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *JobAttempt) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *JobAttempt) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *JobAttempt) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobAttempt) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *JobAttempt) GetFinishedAt() int64 {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return 0
+}
+
+func (x *JobAttempt) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *JobAttempt) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *JobAttempt) GetResult() *ExecutionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type JobHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Attempts      []*JobAttempt          `protobuf:"bytes,2,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobHistory) Reset() {
+	*x = JobHistory{}
+	mi := &file_proto_scheduler_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobHistory) ProtoMessage() {}
+
+func (x *JobHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobHistory.ProtoReflect.Descriptor instead.
+func (*JobHistory) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *JobHistory) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobHistory) GetAttempts() []*JobAttempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+type GetClusterStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClusterStatusRequest) Reset() {
+	*x = GetClusterStatusRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClusterStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClusterStatusRequest) ProtoMessage() {}
+
+func (x *GetClusterStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClusterStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetClusterStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{27}
+}
+
+type ClusterStatus struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	NodeId              string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`                               // This server's identity, hostname-pid
+	Role                string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`                                                 // LEADER, FOLLOWER, or NONE without leader election
+	Election            string                 `protobuf:"bytes,3,opt,name=election,proto3" json:"election,omitempty"`                                         // Election backend, e.g. "etcd" ("" = disabled)
+	LeaderId            string                 `protobuf:"bytes,4,opt,name=leader_id,json=leaderId,proto3" json:"leader_id,omitempty"`                         // Identity of the current leader ("" = unknown or vacant)
+	LeaderEpoch         int64                  `protobuf:"varint,5,opt,name=leader_epoch,json=leaderEpoch,proto3" json:"leader_epoch,omitempty"`               // Fencing epoch of the current leader's term
+	LeaderChangedAt     int64                  `protobuf:"varint,6,opt,name=leader_changed_at,json=leaderChangedAt,proto3" json:"leader_changed_at,omitempty"` // Unix seconds when this server last saw the leader change
+	LeaseTtlSeconds     int64                  `protobuf:"varint,7,opt,name=lease_ttl_seconds,json=leaseTtlSeconds,proto3" json:"lease_ttl_seconds,omitempty"`
+	LeaderDutiesRunning bool                   `protobuf:"varint,8,opt,name=leader_duties_running,json=leaderDutiesRunning,proto3" json:"leader_duties_running,omitempty"` // Whether this server runs the maintenance loops
+	LastScan            *MaintenanceScan       `protobuf:"bytes,9,opt,name=last_scan,json=lastScan,proto3" json:"last_scan,omitempty"`                                     // Latest maintenance tick of this server (unset if it never led)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ClusterStatus) Reset() {
+	*x = ClusterStatus{}
+	mi := &file_proto_scheduler_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterStatus) ProtoMessage() {}
+
+func (x *ClusterStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterStatus.ProtoReflect.Descriptor instead.
+func (*ClusterStatus) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ClusterStatus) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ClusterStatus) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ClusterStatus) GetElection() string {
+	if x != nil {
+		return x.Election
+	}
+	return ""
+}
+
+func (x *ClusterStatus) GetLeaderId() string {
+	if x != nil {
+		return x.LeaderId
+	}
+	return ""
+}
+
+func (x *ClusterStatus) GetLeaderEpoch() int64 {
+	if x != nil {
+		return x.LeaderEpoch
+	}
+	return 0
+}
+
+func (x *ClusterStatus) GetLeaderChangedAt() int64 {
+	if x != nil {
+		return x.LeaderChangedAt
+	}
+	return 0
+}
+
+func (x *ClusterStatus) GetLeaseTtlSeconds() int64 {
+	if x != nil {
+		return x.LeaseTtlSeconds
+	}
+	return 0
+}
+
+func (x *ClusterStatus) GetLeaderDutiesRunning() bool {
+	if x != nil {
+		return x.LeaderDutiesRunning
+	}
+	return false
+}
+
+func (x *ClusterStatus) GetLastScan() *MaintenanceScan {
+	if x != nil {
+		return x.LastScan
+	}
+	return nil
+}
+
+// MaintenanceScan counts what one tick of the leader's maintenance loops did.
+type MaintenanceScan struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	At               int64                  `protobuf:"varint,1,opt,name=at,proto3" json:"at,omitempty"`                                                     // Unix seconds
+	StaleFailed      int64                  `protobuf:"varint,2,opt,name=stale_failed,json=staleFailed,proto3" json:"stale_failed,omitempty"`                // RUNNING jobs marked FAILED after going stale
+	DelayedPromoted  int32                  `protobuf:"varint,3,opt,name=delayed_promoted,json=delayedPromoted,proto3" json:"delayed_promoted,omitempty"`    // Retries moved back to their queues
+	DueEnqueued      int32                  `protobuf:"varint,4,opt,name=due_enqueued,json=dueEnqueued,proto3" json:"due_enqueued,omitempty"`                // Delayed and cron jobs queued
+	OrphansReclaimed int32                  `protobuf:"varint,5,opt,name=orphans_reclaimed,json=orphansReclaimed,proto3" json:"orphans_reclaimed,omitempty"` // Processing entries taken back from dead workers
+	WorkflowSkipped  int64                  `protobuf:"varint,6,opt,name=workflow_skipped,json=workflowSkipped,proto3" json:"workflow_skipped,omitempty"`
+	WorkflowReleased int32                  `protobuf:"varint,7,opt,name=workflow_released,json=workflowReleased,proto3" json:"workflow_released,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *MaintenanceScan) Reset() {
+	*x = MaintenanceScan{}
+	mi := &file_proto_scheduler_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaintenanceScan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceScan) ProtoMessage() {}
+
+func (x *MaintenanceScan) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceScan.ProtoReflect.Descriptor instead.
+func (*MaintenanceScan) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *MaintenanceScan) GetAt() int64 {
+	if x != nil {
+		return x.At
+	}
+	return 0
+}
+
+func (x *MaintenanceScan) GetStaleFailed() int64 {
+	if x != nil {
+		return x.StaleFailed
+	}
+	return 0
+}
+
+func (x *MaintenanceScan) GetDelayedPromoted() int32 {
+	if x != nil {
+		return x.DelayedPromoted
+	}
+	return 0
+}
+
+func (x *MaintenanceScan) GetDueEnqueued() int32 {
+	if x != nil {
+		return x.DueEnqueued
+	}
+	return 0
+}
+
+func (x *MaintenanceScan) GetOrphansReclaimed() int32 {
+	if x != nil {
+		return x.OrphansReclaimed
+	}
+	return 0
+}
+
+func (x *MaintenanceScan) GetWorkflowSkipped() int64 {
+	if x != nil {
+		return x.WorkflowSkipped
+	}
+	return 0
+}
+
+func (x *MaintenanceScan) GetWorkflowReleased() int32 {
+	if x != nil {
+		return x.WorkflowReleased
+	}
+	return 0
+}
+
+type ListWorkersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IncludeDead   bool                   `protobuf:"varint,1,opt,name=include_dead,json=includeDead,proto3" json:"include_dead,omitempty"` // Also list workers whose lease expired
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkersRequest) Reset() {
+	*x = ListWorkersRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkersRequest) ProtoMessage() {}
+
+func (x *ListWorkersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkersRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListWorkersRequest) GetIncludeDead() bool {
+	if x != nil {
+		return x.IncludeDead
+	}
+	return false
+}
+
+type ListWorkersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workers       []*WorkerInfo          `protobuf:"bytes,1,rep,name=workers,proto3" json:"workers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkersResponse) Reset() {
+	*x = ListWorkersResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkersResponse) ProtoMessage() {}
+
+func (x *ListWorkersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkersResponse.ProtoReflect.Descriptor instead.
+func (*ListWorkersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListWorkersResponse) GetWorkers() []*WorkerInfo {
+	if x != nil {
+		return x.Workers
+	}
+	return nil
+}
+
+// WorkerInfo is a worker's entry in the worker registry.
+type WorkerInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Host          string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Pid           int32                  `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Queues        []string               `protobuf:"bytes,5,rep,name=queues,proto3" json:"queues,omitempty"` // Subscriptions as name:weight
+	Slots         int32                  `protobuf:"varint,6,opt,name=slots,proto3" json:"slots,omitempty"`
+	RunningJobs   []string               `protobuf:"bytes,7,rep,name=running_jobs,json=runningJobs,proto3" json:"running_jobs,omitempty"`
+	StartedAt     int64                  `protobuf:"varint,8,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"` // Unix seconds
+	LastSeen      int64                  `protobuf:"varint,9,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`    // Unix seconds of the latest heartbeat
+	Alive         bool                   `protobuf:"varint,10,opt,name=alive,proto3" json:"alive,omitempty"`                         // False once the lease expired without the worker deregistering
+	State         string                 `protobuf:"bytes,11,opt,name=state,proto3" json:"state,omitempty"`                          // RUNNING, PAUSED or DRAINING, as last reported by the worker
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerInfo) Reset() {
+	*x = WorkerInfo{}
+	mi := &file_proto_scheduler_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerInfo) ProtoMessage() {}
+
+func (x *WorkerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerInfo.ProtoReflect.Descriptor instead.
+func (*WorkerInfo) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *WorkerInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetQueues() []string {
+	if x != nil {
+		return x.Queues
+	}
+	return nil
+}
+
+func (x *WorkerInfo) GetSlots() int32 {
+	if x != nil {
+		return x.Slots
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetRunningJobs() []string {
+	if x != nil {
+		return x.RunningJobs
+	}
+	return nil
+}
+
+func (x *WorkerInfo) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetAlive() bool {
+	if x != nil {
+		return x.Alive
+	}
+	return false
+}
+
+func (x *WorkerInfo) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type WorkerControlRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerControlRequest) Reset() {
+	*x = WorkerControlRequest{}
+	mi := &file_proto_scheduler_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerControlRequest) ProtoMessage() {}
+
+func (x *WorkerControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerControlRequest.ProtoReflect.Descriptor instead.
+func (*WorkerControlRequest) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *WorkerControlRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type WorkerControlResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Worker        *WorkerInfo            `protobuf:"bytes,3,opt,name=worker,proto3" json:"worker,omitempty"` // The worker's registry entry when the request was made
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerControlResponse) Reset() {
+	*x = WorkerControlResponse{}
+	mi := &file_proto_scheduler_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerControlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerControlResponse) ProtoMessage() {}
+
+func (x *WorkerControlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_scheduler_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerControlResponse.ProtoReflect.Descriptor instead.
+func (*WorkerControlResponse) Descriptor() ([]byte, []int) {
+	return file_proto_scheduler_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *WorkerControlResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *WorkerControlResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *WorkerControlResponse) GetWorker() *WorkerInfo {
+	if x != nil {
+		return x.Worker
+	}
+	return nil
+}
+
+var File_proto_scheduler_proto protoreflect.FileDescriptor
+
+const file_proto_scheduler_proto_rawDesc = "" +
+	"\n" +
+	"\x15proto/scheduler.proto\x12\tscheduler\"i\n" +
+	"\x04Task\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12#\n" +
+	"\rschedule_time\x18\x04 \x01(\x03R\fscheduleTime\"[\n" +
+	"\fTaskResponse\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x18\n" +
+	"\x06TaskId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"L\n" +
+	"\n" +
+	"TaskStatus\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06result\x18\x03 \x01(\tR\x06result\"\xeb\x02\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\acommand\x18\x02 \x01(\tR\acommand\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12#\n" +
+	"\rschedule_time\x18\x04 \x01(\x03R\fscheduleTime\x12\x1b\n" +
+	"\tcron_expr\x18\x05 \x01(\tR\bcronExpr\x12\x1a\n" +
+	"\btimezone\x18\x06 \x01(\tR\btimezone\x12'\n" +
+	"\x0ftimeout_seconds\x18\a \x01(\x03R\x0etimeoutSeconds\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\x05R\bpriority\x12\x14\n" +
+	"\x05queue\x18\t \x01(\tR\x05queue\x129\n" +
+	"\fretry_policy\x18\n" +
+	" \x01(\v2\x16.scheduler.RetryPolicyR\vretryPolicy\x12'\n" +
+	"\x0fidempotency_key\x18\v \x01(\tR\x0eidempotencyKey\"\xf9\x01\n" +
+	"\vRetryPolicy\x12!\n" +
+	"\fmax_attempts\x18\x01 \x01(\x05R\vmaxAttempts\x126\n" +
+	"\x17initial_backoff_seconds\x18\x02 \x01(\x03R\x15initialBackoffSeconds\x12-\n" +
+	"\x12backoff_multiplier\x18\x03 \x01(\x01R\x11backoffMultiplier\x12.\n" +
+	"\x13max_backoff_seconds\x18\x04 \x01(\x03R\x11maxBackoffSeconds\x120\n" +
+	"\x14retryable_exit_codes\x18\x05 \x03(\x05R\x12retryableExitCodes\"\x8e\x01\n" +
+	"\vJobResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1c\n" +
+	"\tduplicate\x18\x05 \x01(\bR\tduplicate\"\x17\n" +
+	"\x05JobId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xcd\x02\n" +
+	"\tJobStatus\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06output\x18\x03 \x01(\tR\x06output\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\x12\x1e\n" +
+	"\vnext_run_at\x18\x06 \x01(\x03R\tnextRunAt\x12\x1b\n" +
+	"\tcron_expr\x18\a \x01(\tR\bcronExpr\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\x05R\bpriority\x12\x14\n" +
+	"\x05queue\x18\t \x01(\tR\x05queue\x12\x1f\n" +
+	"\vworkflow_id\x18\n" +
+	" \x01(\tR\n" +
+	"workflowId\x122\n" +
+	"\x06result\x18\v \x01(\v2\x1a.scheduler.ExecutionResultR\x06result\"\xfa\x01\n" +
+	"\x0fExecutionResult\x12\x16\n" +
+	"\x06stdout\x18\x01 \x01(\tR\x06stdout\x12\x16\n" +
+	"\x06stderr\x18\x02 \x01(\tR\x06stderr\x12\x1b\n" +
+	"\texit_code\x18\x03 \x01(\x05R\bexitCode\x12\x16\n" +
+	"\x06signal\x18\x04 \x01(\tR\x06signal\x12 \n" +
+	"\fwall_time_ms\x18\x05 \x01(\x03R\n" +
+	"wallTimeMs\x12\x1e\n" +
+	"\vuser_cpu_ms\x18\x06 \x01(\x03R\tuserCpuMs\x12\"\n" +
+	"\rsystem_cpu_ms\x18\a \x01(\x03R\vsystemCpuMs\x12\x1c\n" +
+	"\n" +
+	"max_rss_kb\x18\b \x01(\x03R\bmaxRssKb\"\x8f\x03\n" +
+	"\x0fListJobsRequest\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12#\n" +
+	"\rcreated_after\x18\x02 \x01(\x03R\fcreatedAfter\x12%\n" +
+	"\x0ecreated_before\x18\x03 \x01(\x03R\rcreatedBefore\x12#\n" +
+	"\rupdated_after\x18\x04 \x01(\x03R\fupdatedAfter\x12%\n" +
+	"\x0eupdated_before\x18\x05 \x01(\x03R\rupdatedBefore\x12)\n" +
+	"\x10command_contains\x18\x06 \x01(\tR\x0fcommandContains\x12\x12\n" +
+	"\x04kind\x18\a \x01(\tR\x04kind\x12\x19\n" +
+	"\border_by\x18\b \x01(\tR\aorderBy\x12\x1c\n" +
+	"\tascending\x18\t \x01(\bR\tascending\x12\x1b\n" +
+	"\tpage_size\x18\n" +
+	" \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\v \x01(\tR\tpageToken\x12\x14\n" +
+	"\x05queue\x18\f \x01(\tR\x05queue\"d\n" +
+	"\x10ListJobsResponse\x12(\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x14.scheduler.JobStatusR\x04jobs\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xda\x01\n" +
+	"\n" +
+	"DeadLetter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05queue\x18\x02 \x01(\tR\x05queue\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1f\n" +
+	"\vlast_output\x18\x05 \x01(\tR\n" +
+	"lastOutput\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12\x1b\n" +
+	"\tfailed_at\x18\a \x01(\x03R\bfailedAt\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\x05R\bpriority\"D\n" +
+	"\x16ListDeadLettersRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"i\n" +
+	"\x17ListDeadLettersResponse\x128\n" +
+	"\fdead_letters\x18\x01 \x03(\v2\x15.scheduler.DeadLetterR\vdeadLetters\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\xa3\x01\n" +
+	"\x17PurgeDeadLettersRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x10\n" +
+	"\x03ids\x18\x02 \x03(\tR\x03ids\x12#\n" +
+	"\rfailed_before\x18\x03 \x01(\x03R\ffailedBefore\x12)\n" +
+	"\x10command_contains\x18\x04 \x01(\tR\x0fcommandContains\x12\x10\n" +
+	"\x03all\x18\x05 \x01(\bR\x03all\"2\n" +
+	"\x18PurgeDeadLettersResponse\x12\x16\n" +
+	"\x06purged\x18\x01 \x01(\x05R\x06purged\"c\n" +
+	"\fWorkflowNode\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\x03job\x18\x02 \x01(\v2\x0e.scheduler.JobR\x03job\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x03 \x03(\tR\tdependsOn\"]\n" +
+	"\bWorkflow\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12-\n" +
+	"\x05nodes\x18\x03 \x03(\v2\x17.scheduler.WorkflowNodeR\x05nodes\"\xe4\x01\n" +
+	"\x10WorkflowResponse\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12@\n" +
+	"\ajob_ids\x18\x04 \x03(\v2'.scheduler.WorkflowResponse.JobIdsEntryR\x06jobIds\x1a9\n" +
+	"\vJobIdsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x1c\n" +
+	"\n" +
+	"WorkflowId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x95\x01\n" +
+	"\x12WorkflowNodeStatus\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x04 \x03(\tR\tdependsOn\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\"\xbf\x01\n" +
+	"\x0eWorkflowStatus\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\x123\n" +
+	"\x05nodes\x18\x06 \x03(\v2\x1d.scheduler.WorkflowNodeStatusR\x05nodes\"C\n" +
+	"\x12TailJobLogsRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06follow\x18\x02 \x01(\bR\x06follow\"Y\n" +
+	"\bLogChunk\x12\x16\n" +
+	"\x06stream\x18\x01 \x01(\tR\x06stream\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12!\n" +
+	"\ftimestamp_ms\x18\x03 \x01(\x03R\vtimestampMs\"\x9c\x02\n" +
+	"\n" +
+	"JobAttempt\x12\x18\n" +
+	"\aattempt\x18\x01 \x01(\x05R\aattempt\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12\x12\n" +
+	"\x04host\x18\x03 \x01(\tR\x04host\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\x03R\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\x06 \x01(\x03R\n" +
+	"finishedAt\x12\x1f\n" +
+	"\vduration_ms\x18\a \x01(\x03R\n" +
+	"durationMs\x12\x16\n" +
+	"\x06output\x18\b \x01(\tR\x06output\x122\n" +
+	"\x06result\x18\t \x01(\v2\x1a.scheduler.ExecutionResultR\x06result\"V\n" +
+	"\n" +
+	"JobHistory\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x121\n" +
+	"\battempts\x18\x02 \x03(\v2\x15.scheduler.JobAttemptR\battempts\"\x19\n" +
+	"\x17GetClusterStatusRequest\"\xdd\x02\n" +
+	"\rClusterStatus\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x1a\n" +
+	"\belection\x18\x03 \x01(\tR\belection\x12\x1b\n" +
+	"\tleader_id\x18\x04 \x01(\tR\bleaderId\x12!\n" +
+	"\fleader_epoch\x18\x05 \x01(\x03R\vleaderEpoch\x12*\n" +
+	"\x11leader_changed_at\x18\x06 \x01(\x03R\x0fleaderChangedAt\x12*\n" +
+	"\x11lease_ttl_seconds\x18\a \x01(\x03R\x0fleaseTtlSeconds\x122\n" +
+	"\x15leader_duties_running\x18\b \x01(\bR\x13leaderDutiesRunning\x127\n" +
+	"\tlast_scan\x18\t \x01(\v2\x1a.scheduler.MaintenanceScanR\blastScan\"\x97\x02\n" +
+	"\x0fMaintenanceScan\x12\x0e\n" +
+	"\x02at\x18\x01 \x01(\x03R\x02at\x12!\n" +
+	"\fstale_failed\x18\x02 \x01(\x03R\vstaleFailed\x12)\n" +
+	"\x10delayed_promoted\x18\x03 \x01(\x05R\x0fdelayedPromoted\x12!\n" +
+	"\fdue_enqueued\x18\x04 \x01(\x05R\vdueEnqueued\x12+\n" +
+	"\x11orphans_reclaimed\x18\x05 \x01(\x05R\x10orphansReclaimed\x12)\n" +
+	"\x10workflow_skipped\x18\x06 \x01(\x03R\x0fworkflowSkipped\x12+\n" +
+	"\x11workflow_released\x18\a \x01(\x05R\x10workflowReleased\"7\n" +
+	"\x12ListWorkersRequest\x12!\n" +
+	"\finclude_dead\x18\x01 \x01(\bR\vincludeDead\"F\n" +
+	"\x13ListWorkersResponse\x12/\n" +
+	"\aworkers\x18\x01 \x03(\v2\x15.scheduler.WorkerInfoR\aworkers\"\x95\x02\n" +
+	"\n" +
+	"WorkerInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\x12\x10\n" +
+	"\x03pid\x18\x03 \x01(\x05R\x03pid\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x16\n" +
+	"\x06queues\x18\x05 \x03(\tR\x06queues\x12\x14\n" +
+	"\x05slots\x18\x06 \x01(\x05R\x05slots\x12!\n" +
+	"\frunning_jobs\x18\a \x03(\tR\vrunningJobs\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\x05 \x01(\x03R\tupdatedAt2\x86\x01\n" +
+	"started_at\x18\b \x01(\x03R\tstartedAt\x12\x1b\n" +
+	"\tlast_seen\x18\t \x01(\x03R\blastSeen\x12\x14\n" +
+	"\x05alive\x18\n" +
+	" \x01(\bR\x05alive\x12\x14\n" +
+	"\x05state\x18\v \x01(\tR\x05state\"3\n" +
+	"\x14WorkerControlRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"z\n" +
+	"\x15WorkerControlResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\x06worker\x18\x03 \x01(\v2\x15.scheduler.WorkerInfoR\x06worker2\x86\x01\n" +
 	"\rTaskScheduler\x128\n" +
 	"\n" +
 	"SubmitTask\x12\x0f.scheduler.Task\x1a\x17.scheduler.TaskResponse\"\x00\x12;\n" +
-	"\rGetTaskStatus\x12\x11.scheduler.TaskId\x1a\x15.scheduler.TaskStatus\"\x002}\n" +
+	"\rGetTaskStatus\x12\x11.scheduler.TaskId\x1a\x15.scheduler.TaskStatus\"\x002\xc2\x06\n" +
 	"\n" +
 	"JobService\x125\n" +
 	"\tSubmitJob\x12\x0e.scheduler.Job\x1a\x16.scheduler.JobResponse\"\x00\x128\n" +
-	"\fGetJobStatus\x12\x10.scheduler.JobId\x1a\x14.scheduler.JobStatus\"\x00B\"Z distributed-task-scheduler/protob\x06proto3"
+	"\fGetJobStatus\x12\x10.scheduler.JobId\x1a\x14.scheduler.JobStatus\"\x00\x12E\n" +
+	"\bListJobs\x12\x1a.scheduler.ListJobsRequest\x1a\x1b.scheduler.ListJobsResponse\"\x00\x127\n" +
+	"\tCancelJob\x12\x10.scheduler.JobId\x1a\x16.scheduler.JobResponse\"\x00\x12Z\n" +
+	"\x0fListDeadLetters\x12!.scheduler.ListDeadLettersRequest\x1a\".scheduler.ListDeadLettersResponse\"\x00\x12>\n" +
+	"\x10ReplayDeadLetter\x12\x10.scheduler.JobId\x1a\x16.scheduler.JobResponse\"\x00\x12]\n" +
+	"\x10PurgeDeadLetters\x12\".scheduler.PurgeDeadLettersRequest\x1a#.scheduler.PurgeDeadLettersResponse\"\x00\x12D\n" +
+	"\x0eSubmitWorkflow\x12\x13.scheduler.Workflow\x1a\x1b.scheduler.WorkflowResponse\"\x00\x12G\n" +
+	"\x11GetWorkflowStatus\x12\x15.scheduler.WorkflowId\x1a\x19.scheduler.WorkflowStatus\"\x00\x126\n" +
+	"\bWatchJob\x12\x10.scheduler.JobId\x1a\x14.scheduler.JobStatus\"\x000\x01\x12E\n" +
+	"\vTailJobLogs\x12\x1d.scheduler.TailJobLogsRequest\x1a\x13.scheduler.LogChunk\"\x000\x01\x12:\n" +
+	"\rGetJobHistory\x12\x10.scheduler.JobId\x1a\x15.scheduler.JobHistory\"\x002\xb1\x03\n" +
+	"\x0eClusterService\x12R\n" +
+	"\x10GetClusterStatus\x12\".scheduler.GetClusterStatusRequest\x1a\x18.scheduler.ClusterStatus\"\x00\x12N\n" +
+	"\vListWorkers\x12\x1d.scheduler.ListWorkersRequest\x1a\x1e.scheduler.ListWorkersResponse\"\x00\x12R\n" +
+	"\vDrainWorker\x12\x1f.scheduler.WorkerControlRequest\x1a .scheduler.WorkerControlResponse\"\x00\x12R\n" +
+	"\vPauseWorker\x12\x1f.scheduler.WorkerControlRequest\x1a .scheduler.WorkerControlResponse\"\x00\x12S\n" +
+	"\fResumeWorker\x12\x1f.scheduler.WorkerControlRequest\x1a .scheduler.WorkerControlResponse\"\x00B\"Z distributed-task-scheduler/protob\x06proto3"
 
 var (
 	file_proto_scheduler_proto_rawDescOnce sync.Once
@@ -554,31 +2788,102 @@ func file_proto_scheduler_proto_rawDescGZIP() []byte {
 	return file_proto_scheduler_proto_rawDescData
 }
 
-var file_proto_scheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_scheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_proto_scheduler_proto_goTypes = []any{
-	(*Task)(nil),         // 0: scheduler.Task
-	(*TaskResponse)(nil), // 1: scheduler.TaskResponse
-	(*TaskId)(nil),       // 2: scheduler.TaskId
-	(*TaskStatus)(nil),   // 3: scheduler.TaskStatus
-	(*Job)(nil),          // 4: scheduler.Job
-	(*JobResponse)(nil),  // 5: scheduler.JobResponse
-	(*JobId)(nil),        // 6: scheduler.JobId
-	(*JobStatus)(nil),    // 7: scheduler.JobStatus
+	(*Task)(nil),                     // 0: scheduler.Task
+	(*TaskResponse)(nil),             // 1: scheduler.TaskResponse
+	(*TaskId)(nil),                   // 2: scheduler.TaskId
+	(*TaskStatus)(nil),               // 3: scheduler.TaskStatus
+	(*Job)(nil),                      // 4: scheduler.Job
+	(*RetryPolicy)(nil),              // 5: scheduler.RetryPolicy
+	(*JobResponse)(nil),              // 6: scheduler.JobResponse
+	(*JobId)(nil),                    // 7: scheduler.JobId
+	(*JobStatus)(nil),                // 8: scheduler.JobStatus
+	(*ExecutionResult)(nil),          // 9: scheduler.ExecutionResult
+	(*ListJobsRequest)(nil),          // 10: scheduler.ListJobsRequest
+	(*ListJobsResponse)(nil),         // 11: scheduler.ListJobsResponse
+	(*DeadLetter)(nil),               // 12: scheduler.DeadLetter
+	(*ListDeadLettersRequest)(nil),   // 13: scheduler.ListDeadLettersRequest
+	(*ListDeadLettersResponse)(nil),  // 14: scheduler.ListDeadLettersResponse
+	(*PurgeDeadLettersRequest)(nil),  // 15: scheduler.PurgeDeadLettersRequest
+	(*PurgeDeadLettersResponse)(nil), // 16: scheduler.PurgeDeadLettersResponse
+	(*WorkflowNode)(nil),             // 17: scheduler.WorkflowNode
+	(*Workflow)(nil),                 // 18: scheduler.Workflow
+	(*WorkflowResponse)(nil),         // 19: scheduler.WorkflowResponse
+	(*WorkflowId)(nil),               // 20: scheduler.WorkflowId
+	(*WorkflowNodeStatus)(nil),       // 21: scheduler.WorkflowNodeStatus
+	(*WorkflowStatus)(nil),           // 22: scheduler.WorkflowStatus
+	(*TailJobLogsRequest)(nil),       // 23: scheduler.TailJobLogsRequest
+	(*LogChunk)(nil),                 // 24: scheduler.LogChunk
+	(*JobAttempt)(nil),               // 25: scheduler.JobAttempt
+	(*JobHistory)(nil),               // 26: scheduler.JobHistory
+	(*GetClusterStatusRequest)(nil),  // 27: scheduler.GetClusterStatusRequest
+	(*ClusterStatus)(nil),            // 28: scheduler.ClusterStatus
+	(*MaintenanceScan)(nil),          // 29: scheduler.MaintenanceScan
+	(*ListWorkersRequest)(nil),       // 30: scheduler.ListWorkersRequest
+	(*ListWorkersResponse)(nil),      // 31: scheduler.ListWorkersResponse
+	(*WorkerInfo)(nil),               // 32: scheduler.WorkerInfo
+	(*WorkerControlRequest)(nil),     // 33: scheduler.WorkerControlRequest
+	(*WorkerControlResponse)(nil),    // 34: scheduler.WorkerControlResponse
+	nil,                              // 35: scheduler.WorkflowResponse.JobIdsEntry
 }
 var file_proto_scheduler_proto_depIdxs = []int32{
-	0, // 0: scheduler.TaskScheduler.SubmitTask:input_type -> scheduler.Task
-	2, // 1: scheduler.TaskScheduler.GetTaskStatus:input_type -> scheduler.TaskId
-	4, // 2: scheduler.JobService.SubmitJob:input_type -> scheduler.Job
-	6, // 3: scheduler.JobService.GetJobStatus:input_type -> scheduler.JobId
-	1, // 4: scheduler.TaskScheduler.SubmitTask:output_type -> scheduler.TaskResponse
-	3, // 5: scheduler.TaskScheduler.GetTaskStatus:output_type -> scheduler.TaskStatus
-	5, // 6: scheduler.JobService.SubmitJob:output_type -> scheduler.JobResponse
-	7, // 7: scheduler.JobService.GetJobStatus:output_type -> scheduler.JobStatus
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	5,  // 0: scheduler.Job.retry_policy:type_name -> scheduler.RetryPolicy
+	9,  // 1: scheduler.JobStatus.result:type_name -> scheduler.ExecutionResult
+	8,  // 2: scheduler.ListJobsResponse.jobs:type_name -> scheduler.JobStatus
+	12, // 3: scheduler.ListDeadLettersResponse.dead_letters:type_name -> scheduler.DeadLetter
+	4,  // 4: scheduler.WorkflowNode.job:type_name -> scheduler.Job
+	17, // 5: scheduler.Workflow.nodes:type_name -> scheduler.WorkflowNode
+	35, // 6: scheduler.WorkflowResponse.job_ids:type_name -> scheduler.WorkflowResponse.JobIdsEntry
+	21, // 7: scheduler.WorkflowStatus.nodes:type_name -> scheduler.WorkflowNodeStatus
+	9,  // 8: scheduler.JobAttempt.result:type_name -> scheduler.ExecutionResult
+	25, // 9: scheduler.JobHistory.attempts:type_name -> scheduler.JobAttempt
+	29, // 10: scheduler.ClusterStatus.last_scan:type_name -> scheduler.MaintenanceScan
+	32, // 11: scheduler.ListWorkersResponse.workers:type_name -> scheduler.WorkerInfo
+	32, // 12: scheduler.WorkerControlResponse.worker:type_name -> scheduler.WorkerInfo
+	0,  // 13: scheduler.TaskScheduler.SubmitTask:input_type -> scheduler.Task
+	2,  // 14: scheduler.TaskScheduler.GetTaskStatus:input_type -> scheduler.TaskId
+	4,  // 15: scheduler.JobService.SubmitJob:input_type -> scheduler.Job
+	7,  // 16: scheduler.JobService.GetJobStatus:input_type -> scheduler.JobId
+	10, // 17: scheduler.JobService.ListJobs:input_type -> scheduler.ListJobsRequest
+	7,  // 18: scheduler.JobService.CancelJob:input_type -> scheduler.JobId
+	13, // 19: scheduler.JobService.ListDeadLetters:input_type -> scheduler.ListDeadLettersRequest
+	7,  // 20: scheduler.JobService.ReplayDeadLetter:input_type -> scheduler.JobId
+	15, // 21: scheduler.JobService.PurgeDeadLetters:input_type -> scheduler.PurgeDeadLettersRequest
+	18, // 22: scheduler.JobService.SubmitWorkflow:input_type -> scheduler.Workflow
+	20, // 23: scheduler.JobService.GetWorkflowStatus:input_type -> scheduler.WorkflowId
+	7,  // 24: scheduler.JobService.WatchJob:input_type -> scheduler.JobId
+	23, // 25: scheduler.JobService.TailJobLogs:input_type -> scheduler.TailJobLogsRequest
+	7,  // 26: scheduler.JobService.GetJobHistory:input_type -> scheduler.JobId
+	27, // 27: scheduler.ClusterService.GetClusterStatus:input_type -> scheduler.GetClusterStatusRequest
+	30, // 28: scheduler.ClusterService.ListWorkers:input_type -> scheduler.ListWorkersRequest
+	33, // 29: scheduler.ClusterService.DrainWorker:input_type -> scheduler.WorkerControlRequest
+	33, // 30: scheduler.ClusterService.PauseWorker:input_type -> scheduler.WorkerControlRequest
+	33, // 31: scheduler.ClusterService.ResumeWorker:input_type -> scheduler.WorkerControlRequest
+	1,  // 32: scheduler.TaskScheduler.SubmitTask:output_type -> scheduler.TaskResponse
+	3,  // 33: scheduler.TaskScheduler.GetTaskStatus:output_type -> scheduler.TaskStatus
+	6,  // 34: scheduler.JobService.SubmitJob:output_type -> scheduler.JobResponse
+	8,  // 35: scheduler.JobService.GetJobStatus:output_type -> scheduler.JobStatus
+	11, // 36: scheduler.JobService.ListJobs:output_type -> scheduler.ListJobsResponse
+	6,  // 37: scheduler.JobService.CancelJob:output_type -> scheduler.JobResponse
+	14, // 38: scheduler.JobService.ListDeadLetters:output_type -> scheduler.ListDeadLettersResponse
+	6,  // 39: scheduler.JobService.ReplayDeadLetter:output_type -> scheduler.JobResponse
+	16, // 40: scheduler.JobService.PurgeDeadLetters:output_type -> scheduler.PurgeDeadLettersResponse
+	19, // 41: scheduler.JobService.SubmitWorkflow:output_type -> scheduler.WorkflowResponse
+	22, // 42: scheduler.JobService.GetWorkflowStatus:output_type -> scheduler.WorkflowStatus
+	8,  // 43: scheduler.JobService.WatchJob:output_type -> scheduler.JobStatus
+	24, // 44: scheduler.JobService.TailJobLogs:output_type -> scheduler.LogChunk
+	26, // 45: scheduler.JobService.GetJobHistory:output_type -> scheduler.JobHistory
+	28, // 46: scheduler.ClusterService.GetClusterStatus:output_type -> scheduler.ClusterStatus
+	31, // 47: scheduler.ClusterService.ListWorkers:output_type -> scheduler.ListWorkersResponse
+	34, // 48: scheduler.ClusterService.DrainWorker:output_type -> scheduler.WorkerControlResponse
+	34, // 49: scheduler.ClusterService.PauseWorker:output_type -> scheduler.WorkerControlResponse
+	34, // 50: scheduler.ClusterService.ResumeWorker:output_type -> scheduler.WorkerControlResponse
+	32, // [32:51] is the sub-list for method output_type
+	13, // [13:32] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_proto_scheduler_proto_init() }
@@ -592,9 +2897,9 @@ func file_proto_scheduler_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_scheduler_proto_rawDesc), len(file_proto_scheduler_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   36,
 			NumExtensions: 0,
-			NumServices:   2,
+			NumServices:   3,
 		},
 		GoTypes:           file_proto_scheduler_proto_goTypes,
 		DependencyIndexes: file_proto_scheduler_proto_depIdxs,
@@ -142,7 +142,7 @@ fi
 
 # Run server in background with logging
 print_status "Starting server on port $SERVER_PORT..."
-DATABASE_URL="$DATABASE_URL" make run-server > log/server/server.log 2>&1 &
+AUTO_MIGRATE=true DATABASE_URL="$DATABASE_URL" make run-server > log/server/server.log 2>&1 &
 SERVER_PID=$!
 PIDS+=("$SERVER_PID")
 